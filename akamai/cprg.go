@@ -0,0 +1,124 @@
+package akamai
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// CP Code and Reporting Group API
+//
+// PAPI can create CP codes but cannot rename them or manage their reporting
+// groups, so those operations go through CPRG using the property credentials.
+//
+// https://developer.akamai.com/api/core_features/cp_codes_reporting_groups/v1.html
+
+func cprgCPCodeID(cpCodeID string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(cpCodeID, "cpc_"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid CP code ID %q", cpCodeID)
+	}
+	return id, nil
+}
+
+// renameCPCode changes the name of an existing CP code.
+//
+// Endpoint: PUT /cprg/v1/cpcodes/{cpcodeId}
+func renameCPCode(cpCodeID string, name string) error {
+	id, err := cprgCPCodeID(cpCodeID)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/cprg/v1/cpcodes/%d", id)
+	cpCode := make(map[string]interface{})
+	if err := papiDo("GET", path, nil, &cpCode); err != nil {
+		return err
+	}
+
+	cpCode["cpcodeName"] = name
+	log.Printf("[DEBUG] Renaming CP code %d to %s", id, name)
+	return papiDo("PUT", path, cpCode, nil)
+}
+
+// reportingGroupHasCPCode reports whether a CP code belongs to a reporting group.
+//
+// Endpoint: GET /cprg/v1/reporting-groups/{reportingGroupId}
+func reportingGroupHasCPCode(reportingGroupID int, cpCodeID string) (bool, error) {
+	id, err := cprgCPCodeID(cpCodeID)
+	if err != nil {
+		return false, err
+	}
+
+	group := make(map[string]interface{})
+	if err := papiDo("GET", fmt.Sprintf("/cprg/v1/reporting-groups/%d", reportingGroupID), nil, &group); err != nil {
+		return false, err
+	}
+
+	contracts, _ := group["contracts"].([]interface{})
+	for _, c := range contracts {
+		contract, _ := c.(map[string]interface{})
+		cpCodes, _ := contract["cpcodes"].([]interface{})
+		for _, cpc := range cpCodes {
+			cpCode, _ := cpc.(map[string]interface{})
+			if v, ok := cpCode["cpcodeId"].(float64); ok && int(v) == id {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// setReportingGroupCPCode adds a CP code to, or removes it from, the given
+// contract of a reporting group.
+//
+// Endpoint: PUT /cprg/v1/reporting-groups/{reportingGroupId}
+func setReportingGroupCPCode(reportingGroupID int, contractID string, cpCodeID string, member bool) error {
+	id, err := cprgCPCodeID(cpCodeID)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/cprg/v1/reporting-groups/%d", reportingGroupID)
+	group := make(map[string]interface{})
+	if err := papiDo("GET", path, nil, &group); err != nil {
+		return err
+	}
+
+	contractID = strings.TrimPrefix(contractID, "ctr_")
+	contracts, _ := group["contracts"].([]interface{})
+	found := false
+	for _, c := range contracts {
+		contract, _ := c.(map[string]interface{})
+		if contract["contractId"] != contractID {
+			continue
+		}
+		found = true
+
+		cpCodes, _ := contract["cpcodes"].([]interface{})
+		updated := make([]interface{}, 0, len(cpCodes)+1)
+		for _, cpc := range cpCodes {
+			cpCode, _ := cpc.(map[string]interface{})
+			if v, ok := cpCode["cpcodeId"].(float64); ok && int(v) == id {
+				continue
+			}
+			updated = append(updated, cpc)
+		}
+		if member {
+			updated = append(updated, map[string]interface{}{"cpcodeId": id})
+		}
+		contract["cpcodes"] = updated
+	}
+
+	if !found {
+		if !member {
+			return nil
+		}
+		return fmt.Errorf("reporting group %d is not associated with contract %s", reportingGroupID, contractID)
+	}
+
+	log.Printf("[DEBUG] Updating reporting group %d membership for CP code %d", reportingGroupID, id)
+	return papiDo("PUT", path, group, nil)
+}
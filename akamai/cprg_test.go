@@ -0,0 +1,131 @@
+package akamai
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+)
+
+// testCPRGServer serves CPRG documents from memory, so the CP code and
+// reporting group read-modify-write calls can be checked without an account.
+func testCPRGServer(t *testing.T, documents map[string]map[string]interface{}) func() {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		doc, ok := documents[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(doc)
+		case "PUT":
+			updated := make(map[string]interface{})
+			if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+				t.Errorf("Value %v is invalid: %v", r.URL.Path, err)
+			}
+			documents[r.URL.Path] = updated
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+
+	httpClient, config := client.Client, papi.Config
+	client.Client = srv.Client()
+	papi.Config = edgegrid.Config{Host: srv.URL, ClientToken: "test", ClientSecret: "test", AccessToken: "test", MaxBody: 131072}
+	return func() {
+		client.Client, papi.Config = httpClient, config
+		srv.Close()
+	}
+}
+
+func TestRenameCPCode(t *testing.T) {
+	documents := map[string]map[string]interface{}{
+		"/cprg/v1/cpcodes/123": {"cpcodeId": 123, "cpcodeName": "old", "purgeable": true},
+	}
+	defer testCPRGServer(t, documents)()
+
+	if err := renameCPCode("cpc_123", "new"); err != nil {
+		t.Fatalf("Value %v is invalid: %v", "cpc_123", err)
+	}
+	cpCode := documents["/cprg/v1/cpcodes/123"]
+	if cpCode["cpcodeName"] != "new" || cpCode["purgeable"] != true {
+		t.Errorf("Value %v is invalid", cpCode)
+	}
+
+	if err := renameCPCode("cpc_456", "new"); err == nil {
+		t.Errorf("Value %v should not be found", "cpc_456")
+	}
+	if err := renameCPCode("cpc_abc", "new"); err == nil {
+		t.Errorf("Value %v should be invalid", "cpc_abc")
+	}
+}
+
+func TestReportingGroupCPCode(t *testing.T) {
+	path := "/cprg/v1/reporting-groups/5"
+	documents := map[string]map[string]interface{}{
+		path: {
+			"reportingGroupId": 5,
+			"contracts": []interface{}{
+				map[string]interface{}{"contractId": "1-ABC", "cpcodes": []interface{}{map[string]interface{}{"cpcodeId": 1}}},
+			},
+		},
+	}
+	defer testCPRGServer(t, documents)()
+
+	if member, err := reportingGroupHasCPCode(5, "cpc_123"); err != nil || member {
+		t.Errorf("Value %v is invalid: %v", member, err)
+	}
+
+	if err := setReportingGroupCPCode(5, "ctr_1-ABC", "cpc_123", true); err != nil {
+		t.Fatalf("Value %v is invalid: %v", "cpc_123", err)
+	}
+	if member, err := reportingGroupHasCPCode(5, "cpc_123"); err != nil || !member {
+		t.Errorf("Value %v is invalid: %v", documents[path], err)
+	}
+
+	// Adding twice keeps a single entry.
+	if err := setReportingGroupCPCode(5, "ctr_1-ABC", "cpc_123", true); err != nil {
+		t.Fatalf("Value %v is invalid: %v", "cpc_123", err)
+	}
+	cpCodes := documents[path]["contracts"].([]interface{})[0].(map[string]interface{})["cpcodes"].([]interface{})
+	if len(cpCodes) != 2 {
+		t.Errorf("Value %v is invalid", cpCodes)
+	}
+
+	if err := setReportingGroupCPCode(5, "ctr_1-ABC", "cpc_123", false); err != nil {
+		t.Fatalf("Value %v is invalid: %v", "cpc_123", err)
+	}
+	if member, err := reportingGroupHasCPCode(5, "cpc_123"); err != nil || member {
+		t.Errorf("Value %v is invalid: %v", documents[path], err)
+	}
+	if member, err := reportingGroupHasCPCode(5, "cpc_1"); err != nil || !member {
+		t.Errorf("Value %v is invalid: other CP codes were removed: %v", documents[path], err)
+	}
+
+	if err := setReportingGroupCPCode(5, "ctr_2-DEF", "cpc_123", true); err == nil {
+		t.Errorf("Value %v should not be associated with the reporting group", "ctr_2-DEF")
+	}
+	if err := setReportingGroupCPCode(5, "ctr_2-DEF", "cpc_123", false); err != nil {
+		t.Errorf("Value %v is invalid: %v", "ctr_2-DEF", err)
+	}
+}
+
+func TestIsCPCodeNotInContract(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound} {
+		if !isCPCodeNotInContract(client.APIError{Status: status}) {
+			t.Errorf("Value %v should skip the contract", status)
+		}
+	}
+	for _, err := range []error{client.APIError{Status: http.StatusUnauthorized}, client.APIError{Status: http.StatusInternalServerError}, http.ErrHandlerTimeout} {
+		if isCPCodeNotInContract(err) {
+			t.Errorf("Value %v should fail the import", err)
+		}
+	}
+}
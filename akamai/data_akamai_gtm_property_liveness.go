@@ -0,0 +1,71 @@
+package akamai
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	reportsgtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/reportsgtm-v1"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceGTMPropertyLiveness() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceGTMPropertyLivenessRead,
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"property": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"datacenter_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"alive": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"alive_servers": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"down_servers": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceGTMPropertyLivenessRead(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[DEBUG] dataSourceGTMPropertyLiveness Read")
+
+	domain := d.Get("domain").(string)
+	property := d.Get("property").(string)
+	dcID := d.Get("datacenter_id").(int)
+
+	stat, err := reportsgtm.GetIpStatusPerProperty(domain, property, map[string]string{
+		"mostRecent":   "true",
+		"datacenterId": strconv.Itoa(dcID),
+	})
+	if err != nil {
+		return fmt.Errorf("[Error] GTM dataSourceGTMPropertyLivenessRead: liveness report retrieval failed. %v", err)
+	}
+	alive, down := datacenterServers(stat, dcID)
+	if len(alive)+len(down) == 0 {
+		return fmt.Errorf("[Error] GTM dataSourceGTMPropertyLivenessRead: no liveness data for property %s datacenter %d", property, dcID)
+	}
+	log.Printf("[DEBUG] [Akamai GTMv1] Property [%s] datacenter [%d] alive %v, down %v", property, dcID, alive, down)
+
+	d.Set("alive", len(alive) > 0)
+	d.Set("alive_servers", alive)
+	d.Set("down_servers", down)
+	d.SetId(fmt.Sprintf("%s:%s:%d", domain, property, dcID))
+	return nil
+}
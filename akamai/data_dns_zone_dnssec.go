@@ -0,0 +1,78 @@
+package akamai
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceDNSZoneDNSSec() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDNSZoneDNSSecRead,
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"dnskey_record": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ds_record": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"expected_ttl": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"last_modified_date": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"new_dnskey_record": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"new_ds_record": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"alerts": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDNSZoneDNSSecRead(d *schema.ResourceData, meta interface{}) error {
+	zone := d.Get("zone").(string)
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Start Searching for DNSSEC status %s", zone)
+
+	status, err := getZoneDNSSecStatus(zone)
+	if err != nil {
+		return fmt.Errorf("error looking up DNSSEC status for %q: %s", zone, err)
+	}
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Searching for DNSSEC status [%v]", status)
+
+	d.Set("dnskey_record", status.CurrentRecords.DnskeyRecord)
+	d.Set("ds_record", status.CurrentRecords.DsRecord)
+	d.Set("expected_ttl", status.CurrentRecords.ExpectedTTL)
+	d.Set("last_modified_date", status.CurrentRecords.LastModifiedDate)
+	if status.NewRecords != nil {
+		d.Set("new_dnskey_record", status.NewRecords.DnskeyRecord)
+		d.Set("new_ds_record", status.NewRecords.DsRecord)
+	} else {
+		d.Set("new_dnskey_record", "")
+		d.Set("new_ds_record", "")
+	}
+	d.Set("alerts", status.Alerts)
+	d.SetId(zone)
+
+	return nil
+}
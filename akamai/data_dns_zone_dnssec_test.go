@@ -0,0 +1,41 @@
+package akamai
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceDNSZoneDNSSec_basic(t *testing.T) {
+	dataSourceName := "data.akamai_dns_zone_dnssec.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDNSZoneDNSSec_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "zone", "exampleterraform.io"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "dnskey_record"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "ds_record"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceDNSZoneDNSSec_basic() string {
+	return `provider "akamai" {
+  dns_section = "dns"
+}
+
+data "akamai_dns_zone_dnssec" "test" {
+	zone = "exampleterraform.io"
+}
+
+output "ds" {
+	value = "${data.akamai_dns_zone_dnssec.test.ds_record}"
+}
+`
+}
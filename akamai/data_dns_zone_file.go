@@ -0,0 +1,41 @@
+package akamai
+
+import (
+	"fmt"
+	"log"
+
+	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceDNSZoneFile() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDNSZoneFileRead,
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"zone_file": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceDNSZoneFileRead(d *schema.ResourceData, meta interface{}) error {
+	zone := d.Get("zone").(string)
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Start Searching for zone file %s", zone)
+
+	zonefile, err := dnsv2.GetMasterZoneFile(zone)
+	if err != nil {
+		return fmt.Errorf("error looking up zone file for %q: %s", zone, err)
+	}
+
+	d.Set("zone_file", zonefile)
+	d.SetId(zone)
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package akamai
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceDNSZoneFile_basic(t *testing.T) {
+	dataSourceName := "data.akamai_dns_zone_file.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDNSZoneFile_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "zone", "exampleterraform.io"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "zone_file"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceDNSZoneFile_basic() string {
+	return `provider "akamai" {
+  dns_section = "dns"
+}
+
+data "akamai_dns_zone_file" "test" {
+	zone = "exampleterraform.io"
+}
+`
+}
@@ -0,0 +1,146 @@
+package akamai
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func dataSourceDNSZoneRecordsets() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDNSZoneRecordsetsRead,
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"types": {
+				Type:     schema.TypeSet,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"min_ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"recordsets": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ttl": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"rdata": {
+							Type:     schema.TypeList,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// zoneRecordsetsFilter selects recordsets by type, name glob and TTL range.
+type zoneRecordsetsFilter struct {
+	types  map[string]bool
+	name   string
+	minTTL int
+	maxTTL int
+}
+
+func (f zoneRecordsetsFilter) match(rs dnsv2.Recordset) (bool, error) {
+	if len(f.types) > 0 && !f.types[strings.ToUpper(rs.Type)] {
+		return false, nil
+	}
+	if f.minTTL > 0 && rs.TTL < f.minTTL {
+		return false, nil
+	}
+	if f.maxTTL > 0 && rs.TTL > f.maxTTL {
+		return false, nil
+	}
+	if f.name == "" {
+		return true, nil
+	}
+	return path.Match(strings.ToLower(strings.TrimSuffix(f.name, ".")), strings.ToLower(strings.TrimSuffix(rs.Name, ".")))
+}
+
+func dataSourceDNSZoneRecordsetsRead(d *schema.ResourceData, meta interface{}) error {
+	zone := d.Get("zone").(string)
+
+	filter := zoneRecordsetsFilter{
+		types:  make(map[string]bool),
+		name:   d.Get("name").(string),
+		minTTL: d.Get("min_ttl").(int),
+		maxTTL: d.Get("max_ttl").(int),
+	}
+	for _, t := range d.Get("types").(*schema.Set).List() {
+		filter.types[strings.ToUpper(t.(string))] = true
+	}
+	if filter.maxTTL > 0 && filter.minTTL > filter.maxTTL {
+		return fmt.Errorf("min_ttl %d must not be greater than max_ttl %d", filter.minTTL, filter.maxTTL)
+	}
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Start Searching for recordsets in zone %s", zone)
+
+	live, err := getZoneRecordsets(zone)
+	if err != nil {
+		return fmt.Errorf("error looking up recordsets for %q: %s", zone, err)
+	}
+
+	recordsets := make([]interface{}, 0, len(live))
+	for _, rs := range live {
+		ok, err := filter.match(rs)
+		if err != nil {
+			return fmt.Errorf("invalid name pattern %q: %s", filter.name, err)
+		}
+		if !ok {
+			continue
+		}
+
+		rdata := append([]string(nil), rs.Rdata...)
+		sort.Strings(rdata)
+		recordsets = append(recordsets, map[string]interface{}{
+			"name":  rs.Name,
+			"type":  rs.Type,
+			"ttl":   rs.TTL,
+			"rdata": rdata,
+		})
+	}
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Found %d of %d recordsets in zone %s", len(recordsets), len(live), zone)
+
+	if err := d.Set("recordsets", recordsets); err != nil {
+		return err
+	}
+	d.SetId(zone)
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package akamai
+
+import (
+	"testing"
+
+	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceDNSZoneRecordsets_basic(t *testing.T) {
+	dataSourceName := "data.akamai_dns_zone_recordsets.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAkamaiDNSv2RecordDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDNSZoneRecordsets_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "recordsets.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "recordsets.0.type", "A"),
+					resource.TestCheckResourceAttr(dataSourceName, "recordsets.0.rdata.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceDNSZoneRecordsets_basic() string {
+	return `provider "akamai" {
+  dns_section = "dns"
+}
+
+resource "akamai_dns_record" "test" {
+	zone = "exampleterraform.io"
+	name = "www.exampleterraform.io"
+	recordtype =  "A"
+	active = true
+	ttl = 300
+	target = ["10.0.0.2","10.0.0.3"]
+}
+
+data "akamai_dns_zone_recordsets" "test" {
+	zone = "${akamai_dns_record.test.zone}"
+	types = ["A"]
+	name = "www.*"
+	min_ttl = 60
+	max_ttl = 3600
+}
+`
+}
+
+func TestZoneRecordsetsFilter(t *testing.T) {
+	filter := zoneRecordsetsFilter{
+		types:  map[string]bool{"A": true, "AAAA": true},
+		name:   "*.example.com",
+		minTTL: 60,
+		maxTTL: 3600,
+	}
+
+	matches := []dnsv2.Recordset{
+		{Name: "www.example.com", Type: "A", TTL: 300},
+		{Name: "API.Example.com.", Type: "aaaa", TTL: 60},
+	}
+	others := []dnsv2.Recordset{
+		{Name: "example.com", Type: "A", TTL: 300},
+		{Name: "www.example.com", Type: "CNAME", TTL: 300},
+		{Name: "www.example.com", Type: "A", TTL: 30},
+		{Name: "www.example.com", Type: "A", TTL: 86400},
+	}
+
+	for _, rs := range matches {
+		if ok, err := filter.match(rs); !ok || err != nil {
+			t.Errorf("Value %v is invalid: %v", rs, err)
+		}
+	}
+	for _, rs := range others {
+		if ok, err := filter.match(rs); ok || err != nil {
+			t.Errorf("Value %v should not match: %v", rs, err)
+		}
+	}
+
+	if _, err := (zoneRecordsetsFilter{name: "[www"}).match(matches[0]); err == nil {
+		t.Errorf("Value %v should be an invalid pattern", "[www")
+	}
+}
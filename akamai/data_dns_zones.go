@@ -0,0 +1,133 @@
+package akamai
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceDNSZones() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDNSZonesRead,
+		Schema: map[string]*schema.Schema{
+			"contract": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateZoneType,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"sign_and_serve": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"zones": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"zone": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"contract": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"comment": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"sign_and_serve": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"activation_state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"last_activation_date": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"last_modified_by": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"last_modified_date": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"version_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDNSZonesRead(d *schema.ResourceData, meta interface{}) error {
+	contract := strings.TrimPrefix(d.Get("contract").(string), "ctr_")
+	name := strings.ToLower(d.Get("name").(string))
+
+	var zonetypes []string
+	if zonetype := d.Get("type").(string); zonetype != "" {
+		zonetypes = append(zonetypes, strings.ToUpper(zonetype))
+	}
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Start Searching for zones [%s] [%v] [%s]", contract, zonetypes, name)
+
+	list, err := listZones(contract, zonetypes, name)
+	if err != nil {
+		return fmt.Errorf("error looking up zones: %s", err)
+	}
+
+	signandserve, filterSignAndServe := d.GetOkExists("sign_and_serve")
+
+	zones := make([]interface{}, 0, len(list))
+	for _, z := range list {
+		if name != "" && !strings.Contains(strings.ToLower(z.Zone), name) {
+			continue
+		}
+		if filterSignAndServe && z.SignAndServe != signandserve.(bool) {
+			continue
+		}
+
+		zones = append(zones, map[string]interface{}{
+			"zone":                 z.Zone,
+			"type":                 z.Type,
+			"contract":             z.ContractID,
+			"comment":              z.Comment,
+			"sign_and_serve":       z.SignAndServe,
+			"activation_state":     z.ActivationState,
+			"last_activation_date": z.LastActivationDate,
+			"last_modified_by":     z.LastModifiedBy,
+			"last_modified_date":   z.LastModifiedDate,
+			"version_id":           z.VersionID,
+		})
+	}
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Found %d of %d zones", len(zones), len(list))
+
+	if err := d.Set("zones", zones); err != nil {
+		return err
+	}
+	d.SetId(fmt.Sprintf("%s:%s:%s", contract, strings.Join(zonetypes, ","), name))
+
+	return nil
+}
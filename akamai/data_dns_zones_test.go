@@ -0,0 +1,39 @@
+package akamai
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceDNSZones_basic(t *testing.T) {
+	dataSourceName := "data.akamai_dns_zones.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDNSZones_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "zones.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "zones.0.zone", "exampleterraform.io"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "zones.0.version_id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceDNSZones_basic() string {
+	return `provider "akamai" {
+  dns_section = "dns"
+}
+
+data "akamai_dns_zones" "test" {
+	type = "primary"
+	name = "exampleterraform.io"
+	sign_and_serve = false
+}
+`
+}
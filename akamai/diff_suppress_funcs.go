@@ -24,6 +24,40 @@ func suppressEquivalentTypeStringBoolean(k, old, new string, d *schema.ResourceD
 	return false
 }
 
+// suppressEquivalentVariablesDiffs suppresses differences in the order of
+// variables within a variables JSON document.
+func suppressEquivalentVariablesDiffs(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+
+	oldVariables := gjson.Get(old, "variables")
+	newVariables := gjson.Get(new, "variables")
+	if !oldVariables.IsArray() || !newVariables.IsArray() {
+		return false
+	}
+
+	oldByName := make(map[string]string)
+	oldVariables.ForEach(func(key, value gjson.Result) bool {
+		oldByName[value.Get("name").String()] = value.Raw
+		return true
+	})
+
+	newCount := 0
+	equal := true
+	newVariables.ForEach(func(key, value gjson.Result) bool {
+		newCount++
+		oldRaw, ok := oldByName[value.Get("name").String()]
+		if !ok || !jsonBytesEqual([]byte(oldRaw), []byte(value.Raw)) {
+			equal = false
+			return false
+		}
+		return true
+	})
+
+	return equal && newCount == len(oldByName)
+}
+
 func suppressEquivalentJsonDiffs(k, old, new string, d *schema.ResourceData) bool {
 	ob := bytes.NewBufferString("")
 	if err := json.Compact(ob, []byte(old)); err != nil {
@@ -38,6 +72,15 @@ func suppressEquivalentJsonDiffs(k, old, new string, d *schema.ResourceData) boo
 	log.Printf("[DEBUG] suppressEquivalentJsonDiffs NB %s\n", string(nb.Bytes()))
 
 	rulesOld, err := getRulesForComp(d, old)
+	if err != nil {
+		return false
+	}
+	rulesNew, err := getRulesForComp(d, new)
+	if err != nil {
+		return false
+	}
+	canonicalIncludeBehaviors(rulesOld.Rule, rulesNew.Rule)
+
 	rulesOld.Etag = ""
 	jsonBody, err := jsonhooks.Marshal(rulesOld)
 	if err != nil {
@@ -47,7 +90,6 @@ func suppressEquivalentJsonDiffs(k, old, new string, d *schema.ResourceData) boo
 
 	log.Printf("[DEBUG] suppressEquivalentJsonDiffs SHA from OLD Json %s\n", sha1hashOld)
 
-	rulesNew, err := getRulesForComp(d, new)
 	rulesNew.Etag = ""
 	jsonBodyNew, err := jsonhooks.Marshal(rulesNew)
 	if err != nil {
@@ -81,6 +123,15 @@ func suppressEquivalentJsonPendingDiffs(old, new string, d *schema.ResourceDiff)
 	log.Printf("[DEBUG] suppressEquivalentJsonDiffs NB %s\n", string(nb.Bytes()))
 
 	rulesOld, err := getRulesForComp(d, old)
+	if err != nil {
+		return false
+	}
+	rulesNew, err := getRulesForComp(d, new)
+	if err != nil {
+		return false
+	}
+	canonicalIncludeBehaviors(rulesOld.Rule, rulesNew.Rule)
+
 	rulesOld.Etag = ""
 	jsonBody, err := jsonhooks.Marshal(rulesOld)
 	if err != nil {
@@ -90,7 +141,6 @@ func suppressEquivalentJsonPendingDiffs(old, new string, d *schema.ResourceDiff)
 
 	log.Printf("[DEBUG] suppressEquivalentJsonDiffs SHA from OLD Json %s\n", sha1hashOld)
 
-	rulesNew, err := getRulesForComp(d, new)
 	rulesNew.Etag = ""
 	jsonBodyNew, err := jsonhooks.Marshal(rulesNew)
 	if err != nil {
@@ -175,7 +225,9 @@ func getRulesForComp(d interface{}, json string) (*papi.Rules, error) {
 	}
 
 	log.Printf("[DEBUG] updateStandardBehaviors")
-	updateStandardBehaviors(rules, cpCode, origin)
+	if err := updateStandardBehaviors(rules, cpCode, origin); err != nil {
+		return nil, err
+	}
 	log.Printf("[DEBUG] fixupPerformanceBehaviors")
 	fixupPerformanceBehaviors(rules)
 
@@ -0,0 +1,404 @@
+package akamai
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+)
+
+// Edge DNS Changelists
+//
+// configdns-v2 can create, look up and submit a changelist, but cannot list
+// a whole zone or edit the recordsets of a changelist, so those calls are made
+// directly with the DNS credentials.
+//
+// https://developer.akamai.com/api/cloud_security/edge_dns_zone_management/v2.html
+
+const zoneRecordsetsPageSize = 1000
+
+type zoneRecordsets struct {
+	Metadata struct {
+		Page          int `json:"page"`
+		PageSize      int `json:"pageSize"`
+		TotalElements int `json:"totalElements"`
+	} `json:"metadata"`
+	Recordsets []dnsv2.Recordset `json:"recordsets"`
+}
+
+// getZoneRecordsets returns every recordset in a zone.
+//
+// Endpoint: GET /config-dns/v2/zones/{zone}/recordsets{?page,pageSize}
+func getZoneRecordsets(zone string) ([]dnsv2.Recordset, error) {
+	recordsets, err := listRecordsets(fmt.Sprintf("/config-dns/v2/zones/%s/recordsets", url.PathEscape(zone)))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Read %d recordsets from zone %s", len(recordsets), zone)
+	return recordsets, nil
+}
+
+// getChangelistRecordsets returns every recordset in the zone's changelist.
+//
+// Endpoint: GET /config-dns/v2/changelists/{zone}/recordsets{?page,pageSize}
+func getChangelistRecordsets(zone string) ([]dnsv2.Recordset, error) {
+	recordsets, err := listRecordsets(fmt.Sprintf("/config-dns/v2/changelists/%s/recordsets", url.PathEscape(zone)))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Read %d recordsets from changelist of zone %s", len(recordsets), zone)
+	return recordsets, nil
+}
+
+// listRecordsets reads every page of a recordset listing.
+func listRecordsets(path string) ([]dnsv2.Recordset, error) {
+	var recordsets []dnsv2.Recordset
+	for page := 1; ; page++ {
+		var res zoneRecordsets
+		if err := dnsDo("GET", fmt.Sprintf("%s?page=%d&pageSize=%d", path, page, zoneRecordsetsPageSize), nil, &res); err != nil {
+			return nil, err
+		}
+
+		recordsets = append(recordsets, res.Recordsets...)
+		if len(res.Recordsets) == 0 || len(recordsets) >= res.Metadata.TotalElements {
+			break
+		}
+	}
+	return recordsets, nil
+}
+
+// replaceChangelistRecordsets replaces the content of the zone's changelist.
+//
+// Endpoint: PUT /config-dns/v2/changelists/{zone}/recordsets
+func replaceChangelistRecordsets(zone string, recordsets []dnsv2.Recordset) error {
+	body := map[string]interface{}{"recordsets": recordsets}
+	return dnsDo("PUT", fmt.Sprintf("/config-dns/v2/changelists/%s/recordsets", url.PathEscape(zone)), body, nil)
+}
+
+// deleteChangelist discards the zone's changelist without submitting it.
+//
+// Endpoint: DELETE /config-dns/v2/changelists/{zone}
+func deleteChangelist(zone string) error {
+	return dnsDo("DELETE", fmt.Sprintf("/config-dns/v2/changelists/%s", url.PathEscape(zone)), nil, nil)
+}
+
+// submitZoneRecordsets makes recordsets the complete content of the zone with
+// a single changelist, so the number of API calls does not depend on the number
+// of records.
+func submitZoneRecordsets(zone string, recordsets []dnsv2.Recordset) error {
+	if _, err := dnsv2.GetChangeList(zone); err == nil {
+		return fmt.Errorf("a changelist for zone %s already exists, submit or delete it before applying changes", zone)
+	} else if !(dnsv2.IsConfigDNSError(err) && err.(dnsv2.ConfigDNSError).NotFound()) {
+		return err
+	}
+
+	zonecreate := dnsv2.ZoneCreate{Zone: zone}
+	if err := zonecreate.SaveChangelist(); err != nil {
+		return err
+	}
+
+	if err := replaceChangelistRecordsets(zone, recordsets); err != nil {
+		log.Printf("[DEBUG] [Akamai DNSv2] Discarding changelist for zone %s", zone)
+		if e := deleteChangelist(zone); e != nil {
+			log.Printf("[WARN] [Akamai DNSv2] Unable to discard changelist for zone %s: %s", zone, e)
+		}
+		return err
+	}
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Submitting changelist for zone %s with %d recordsets", zone, len(recordsets))
+	return zonecreate.SubmitChangelist()
+}
+
+// recordsetKey identifies a recordset within a zone.
+func recordsetKey(name string, recordtype string) string {
+	return strings.ToLower(strings.TrimSuffix(name, ".")) + "/" + strings.ToUpper(recordtype)
+}
+
+// normalizeRecordset returns a copy of rs with its name, type and rdata in
+// canonical form.
+func normalizeRecordset(rs dnsv2.Recordset) dnsv2.Recordset {
+	recordtype := strings.ToUpper(rs.Type)
+	return dnsv2.Recordset{
+		Name:  strings.ToLower(strings.TrimSuffix(rs.Name, ".")),
+		Type:  recordtype,
+		TTL:   rs.TTL,
+		Rdata: normalizeRecordsetRdata(recordtype, rs.Rdata),
+	}
+}
+
+// diffRecordsets compares the live recordsets of a zone with the desired ones
+// and returns the recordsets to add, change and remove.
+func diffRecordsets(live []dnsv2.Recordset, desired []dnsv2.Recordset) (add []dnsv2.Recordset, edit []dnsv2.Recordset, remove []dnsv2.Recordset) {
+	current := make(map[string]dnsv2.Recordset, len(live))
+	for _, rs := range live {
+		rs = normalizeRecordset(rs)
+		current[recordsetKey(rs.Name, rs.Type)] = rs
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, rs := range desired {
+		rs = normalizeRecordset(rs)
+		key := recordsetKey(rs.Name, rs.Type)
+		wanted[key] = true
+
+		existing, ok := current[key]
+		if !ok {
+			add = append(add, rs)
+			continue
+		}
+		if existing.TTL != rs.TTL || strings.Join(existing.Rdata, "\n") != strings.Join(rs.Rdata, "\n") {
+			edit = append(edit, rs)
+		}
+	}
+
+	for key, rs := range current {
+		if !wanted[key] {
+			remove = append(remove, rs)
+		}
+	}
+	sort.Slice(remove, func(i, j int) bool {
+		return recordsetKey(remove[i].Name, remove[i].Type) < recordsetKey(remove[j].Name, remove[j].Type)
+	})
+
+	return add, edit, remove
+}
+
+// isZoneApexRecordset reports whether rs is the SOA or the apex NS recordset,
+// which Edge DNS requires in every zone.
+func isZoneApexRecordset(zone string, rs dnsv2.Recordset) bool {
+	if !strings.EqualFold(strings.TrimSuffix(rs.Name, "."), strings.TrimSuffix(zone, ".")) {
+		return false
+	}
+	recordtype := strings.ToUpper(rs.Type)
+	return recordtype == RRTypeSoa || recordtype == RRTypeNs
+}
+
+func isNotFoundError(err error) bool {
+	if apiErr, ok := err.(client.APIError); ok {
+		return apiErr.Status == 404
+	}
+	return dnsv2.IsConfigDNSError(err) && err.(dnsv2.ConfigDNSError).NotFound()
+}
+
+// submitRecordset replaces a single recordset of a zone with a changelist, so
+// all of its rdata changes at once. A recordset without rdata is removed. Only
+// the affected recordset is staged; the rest of the zone is left untouched.
+func submitRecordset(zone string, rs dnsv2.Recordset) error {
+	return withZoneLock(zone, func() error {
+		existing, err := getRecordSet(zone, rs.Name, rs.Type)
+		if err != nil {
+			return err
+		}
+
+		op := changelistOp(existing, rs)
+		if op == "" {
+			return nil
+		}
+
+		if _, err := dnsv2.GetChangeList(zone); err == nil {
+			return fmt.Errorf("a changelist for zone %s already exists, submit or delete it before applying changes", zone)
+		} else if !isNotFoundError(err) {
+			return err
+		}
+
+		zonecreate := dnsv2.ZoneCreate{Zone: zone}
+		if err := zonecreate.SaveChangelist(); err != nil {
+			return err
+		}
+
+		if err := addChangelistChange(zone, op, rs); err != nil {
+			log.Printf("[DEBUG] [Akamai DNSv2] Discarding changelist for zone %s", zone)
+			if e := deleteChangelist(zone); e != nil {
+				log.Printf("[WARN] [Akamai DNSv2] Unable to discard changelist for zone %s: %s", zone, e)
+			}
+			return err
+		}
+
+		log.Printf("[DEBUG] [Akamai DNSv2] Submitting changelist for zone %s with %s of %s %s", zone, op, rs.Type, rs.Name)
+		return zonecreate.SubmitChangelist()
+	})
+}
+
+// changelistOp returns the changelist operation that turns the existing
+// recordset into rs, or "" when nothing changes.
+func changelistOp(existing *dnsv2.Recordset, rs dnsv2.Recordset) string {
+	switch {
+	case existing == nil || len(existing.Rdata) == 0:
+		if len(rs.Rdata) == 0 {
+			return ""
+		}
+		return "ADD"
+	case len(rs.Rdata) == 0:
+		return "DELETE"
+	}
+
+	current, wanted := normalizeRecordset(*existing), normalizeRecordset(rs)
+	if current.TTL == wanted.TTL && strings.Join(current.Rdata, "\n") == strings.Join(wanted.Rdata, "\n") {
+		return ""
+	}
+	return "EDIT"
+}
+
+// addChangelistChange stages a single recordset change in the zone's
+// changelist. op is one of ADD, EDIT or DELETE.
+//
+// Endpoint: POST /config-dns/v2/changelists/{zone}/recordsets/add-change
+func addChangelistChange(zone string, op string, rs dnsv2.Recordset) error {
+	body := map[string]interface{}{
+		"name": rs.Name,
+		"type": rs.Type,
+		"op":   op,
+	}
+	if op != "DELETE" {
+		body["ttl"] = rs.TTL
+		body["rdata"] = rs.Rdata
+	}
+	return dnsDo("POST", fmt.Sprintf("/config-dns/v2/changelists/%s/recordsets/add-change", url.PathEscape(zone)), body, nil)
+}
+
+// stageRecordset replaces a single recordset in the zone's changelist, and
+// creates the changelist from the live zone if there is none. A recordset
+// without rdata is removed. Nothing is served until the changelist is
+// submitted.
+func stageRecordset(zone string, rs dnsv2.Recordset) error {
+	return withZoneLock(zone, func() error {
+		if _, err := dnsv2.GetChangeList(zone); err != nil {
+			if !isNotFoundError(err) {
+				return err
+			}
+			log.Printf("[DEBUG] [Akamai DNSv2] Creating changelist for zone %s", zone)
+			zonecreate := dnsv2.ZoneCreate{Zone: zone}
+			if err := zonecreate.SaveChangelist(); err != nil {
+				return err
+			}
+		}
+
+		staged, err := getChangelistRecordsets(zone)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("[DEBUG] [Akamai DNSv2] Staging %s %s in changelist of zone %s", rs.Type, rs.Name, zone)
+		return replaceChangelistRecordsets(zone, replaceRecordset(staged, rs))
+	})
+}
+
+// getStagedRecordSet returns the recordset for name and type from the zone's
+// changelist, or from the zone when it has no changelist.
+func getStagedRecordSet(zone string, host string, recordtype string) (*dnsv2.Recordset, error) {
+	if _, err := dnsv2.GetChangeList(zone); err != nil {
+		if isNotFoundError(err) {
+			return getRecordSet(zone, host, recordtype)
+		}
+		return nil, err
+	}
+
+	staged, err := getChangelistRecordsets(zone)
+	if err != nil {
+		return nil, err
+	}
+	key := recordsetKey(host, recordtype)
+	for _, rs := range staged {
+		if recordsetKey(rs.Name, rs.Type) == key {
+			rs.Rdata = normalizeRecordsetRdata(recordtype, rs.Rdata)
+			return &rs, nil
+		}
+	}
+	return nil, nil
+}
+
+// submitStagedChangelist submits the zone's changelist and returns its diff
+// against the live zone. It does nothing when the zone has no changelist, and
+// discards a changelist without changes.
+func submitStagedChangelist(zone string) ([]string, error) {
+	var diff []string
+	err := withZoneLock(zone, func() error {
+		if _, err := dnsv2.GetChangeList(zone); err != nil {
+			if isNotFoundError(err) {
+				log.Printf("[DEBUG] [Akamai DNSv2] No changelist to submit for zone %s", zone)
+				return nil
+			}
+			return err
+		}
+
+		staged, err := getChangelistRecordsets(zone)
+		if err != nil {
+			return err
+		}
+		live, err := getZoneRecordsets(zone)
+		if err != nil {
+			return err
+		}
+
+		diff = changelistDiff(live, staged)
+		for _, line := range diff {
+			log.Printf("[INFO] [Akamai DNSv2] Changelist for zone %s: %s", zone, line)
+		}
+		if len(diff) == 0 {
+			log.Printf("[DEBUG] [Akamai DNSv2] Discarding changelist without changes for zone %s", zone)
+			return deleteChangelist(zone)
+		}
+
+		zonecreate := dnsv2.ZoneCreate{Zone: zone}
+		return zonecreate.SubmitChangelist()
+	})
+	return diff, err
+}
+
+// changelistDiff returns the records removed ("- ") and added ("+ ") by the
+// staged recordsets, one "name ttl type rdata" line per record.
+func changelistDiff(live []dnsv2.Recordset, staged []dnsv2.Recordset) []string {
+	lines := func(recordsets []dnsv2.Recordset) map[string]bool {
+		normalized := make([]dnsv2.Recordset, 0, len(recordsets))
+		for _, rs := range recordsets {
+			normalized = append(normalized, normalizeRecordset(rs))
+		}
+		result := make(map[string]bool)
+		for _, line := range zoneFileRecords(normalized) {
+			result[line] = true
+		}
+		return result
+	}
+	before, after := lines(live), lines(staged)
+
+	var diff []string
+	for line := range before {
+		if !after[line] {
+			diff = append(diff, "- "+line)
+		}
+	}
+	for line := range after {
+		if !before[line] {
+			diff = append(diff, "+ "+line)
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		if diff[i][2:] != diff[j][2:] {
+			return diff[i][2:] < diff[j][2:]
+		}
+		return diff[i] < diff[j]
+	})
+	return diff
+}
+
+// replaceRecordset returns recordsets with the recordset of the same name and
+// type as rs replaced by rs, or removed when rs has no rdata.
+func replaceRecordset(recordsets []dnsv2.Recordset, rs dnsv2.Recordset) []dnsv2.Recordset {
+	key := recordsetKey(rs.Name, rs.Type)
+	result := make([]dnsv2.Recordset, 0, len(recordsets)+1)
+	for _, r := range recordsets {
+		if recordsetKey(r.Name, r.Type) != key {
+			result = append(result, r)
+		}
+	}
+	if len(rs.Rdata) > 0 {
+		result = append(result, rs)
+	}
+	return result
+}
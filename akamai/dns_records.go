@@ -0,0 +1,77 @@
+package akamai
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+)
+
+// Edge DNS Record Writes
+//
+// configdns-v2 serializes every record write behind a single lock and hides
+// the HTTP status of failed writes, so records are written directly with the
+// DNS credentials. Writes to the same zone are serialized by zone, and
+// retried when Edge DNS reports a conflicting change to the zone.
+
+const (
+	dnsConflictRetries  = 5
+	dnsConflictInterval = 2 * time.Second
+)
+
+var zoneLocks = newNamedLocks()
+
+// lockZone locks a zone for writing and returns the function that unlocks it.
+func lockZone(zone string) func() {
+	return zoneLocks.lock(strings.ToLower(strings.TrimSuffix(zone, ".")))
+}
+
+// withZoneLock runs write while holding the zone lock, and retries it while it
+// fails with a conflicting change to the zone.
+func withZoneLock(zone string, write func() error) error {
+	unlock := lockZone(zone)
+	defer unlock()
+
+	interval := dnsConflictInterval
+	for attempt := 1; ; attempt++ {
+		err := write()
+		if err == nil || !isDNSConflict(err) || attempt == dnsConflictRetries {
+			return err
+		}
+
+		log.Printf("[DEBUG] [Akamai DNSv2] Conflicting change to zone %s, retrying in %v: %s", zone, interval, err)
+		time.Sleep(interval)
+		interval *= 2
+	}
+}
+
+// isDNSConflict reports whether err is Edge DNS rejecting a write because of
+// another change to the same zone.
+func isDNSConflict(err error) bool {
+	apiErr, ok := err.(client.APIError)
+	if !ok {
+		return false
+	}
+	if apiErr.Status == http.StatusConflict {
+		return true
+	}
+	return apiErr.Status == http.StatusBadRequest && strings.Contains(strings.ToLower(apiErr.Title+" "+apiErr.Detail), "changelist")
+}
+
+// writeRecord creates (POST), replaces (PUT) or deletes (DELETE) a recordset.
+//
+// Endpoint: /config-dns/v2/zones/{zone}/names/{name}/types/{type}
+func writeRecord(method string, zone string, record dnsv2.RecordBody) error {
+	path := fmt.Sprintf("/config-dns/v2/zones/%s/names/%s/types/%s", url.PathEscape(zone), url.PathEscape(record.Name), url.PathEscape(record.RecordType))
+
+	var body interface{}
+	if method != "DELETE" {
+		body = record
+	}
+	return dnsDo(method, path, body, nil)
+}
@@ -0,0 +1,71 @@
+package akamai
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+)
+
+func TestIsDNSConflict(t *testing.T) {
+	conflicts := []error{
+		client.APIError{Status: 409, Title: "Conflict"},
+		client.APIError{Status: 400, Title: "Bad Request", Detail: "A changelist already exists for zone example.com"},
+	}
+	others := []error{
+		client.APIError{Status: 400, Title: "Bad Request", Detail: "Invalid rdata"},
+		client.APIError{Status: 404, Title: "Not Found"},
+		errors.New("conflict"),
+	}
+
+	for _, err := range conflicts {
+		if !isDNSConflict(err) {
+			t.Errorf("Value %v is invalid: should be a conflict", err)
+		}
+	}
+	for _, err := range others {
+		if isDNSConflict(err) {
+			t.Errorf("Value %v is invalid: should not be a conflict", err)
+		}
+	}
+}
+
+func TestLockZone(t *testing.T) {
+	unlock := lockZone("example.com")
+
+	done := make(chan bool)
+	go func() {
+		lockZone("example.net")()
+		done <- true
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("a lock on another zone should not block")
+	}
+
+	go func() {
+		lockZone("EXAMPLE.com.")()
+		done <- true
+	}()
+	select {
+	case <-done:
+		t.Fatal("a lock on the same zone should block")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	<-done
+}
+
+func TestWithZoneLockErrors(t *testing.T) {
+	calls := 0
+	err := withZoneLock("example.com", func() error {
+		calls++
+		return client.APIError{Status: 400, Title: "Bad Request", Detail: "Invalid rdata"}
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("Value %v is invalid: %d calls", err, calls)
+	}
+}
@@ -0,0 +1,317 @@
+package akamai
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+)
+
+// Master zone files
+//
+// configdns-v2 can download a zone as a BIND master file but cannot upload one,
+// so the upload is made directly with the DNS credentials. Zone files are
+// parsed into recordsets so they can be compared record by record.
+
+// uploadZoneFile replaces the records of a primary zone with the records of a
+// BIND master file.
+//
+// Endpoint: POST /config-dns/v2/zones/{zone}/zone-file
+func uploadZoneFile(zone string, contents string) error {
+	req, err := client.NewRequest(dnsv2.Config, "POST", fmt.Sprintf("/config-dns/v2/zones/%s/zone-file", url.PathEscape(zone)), strings.NewReader(contents))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/dns")
+
+	res, err := client.Do(dnsv2.Config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	return nil
+}
+
+// parseZoneFile parses a BIND master file for zone into normalized recordsets.
+// $ORIGIN and $TTL directives, relative names, inherited owners and
+// parenthesized multi-line records are supported; $INCLUDE is not.
+func parseZoneFile(zone string, contents string) ([]dnsv2.Recordset, error) {
+	origin := strings.ToLower(fqdn(zone))
+	defaultTTL := -1
+	lastTTL := -1
+	owner := ""
+
+	var order []string
+	recordsets := make(map[string]*dnsv2.Recordset)
+
+	lines, err := zoneFileLines(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		tokens := line.tokens
+		if len(tokens) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(tokens[0]) {
+		case "$ORIGIN":
+			if len(tokens) != 2 {
+				return nil, fmt.Errorf("line %d: invalid $ORIGIN directive", line.number)
+			}
+			origin = strings.ToLower(absoluteName(tokens[1], origin))
+			continue
+		case "$TTL":
+			if len(tokens) != 2 {
+				return nil, fmt.Errorf("line %d: invalid $TTL directive", line.number)
+			}
+			ttl, err := parseZoneFileTTL(tokens[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", line.number, err)
+			}
+			defaultTTL = ttl
+			continue
+		case "$INCLUDE", "$GENERATE":
+			return nil, fmt.Errorf("line %d: %s is not supported", line.number, tokens[0])
+		}
+
+		if !line.inherit {
+			owner = strings.ToLower(absoluteName(tokens[0], origin))
+			tokens = tokens[1:]
+		}
+		if owner == "" {
+			return nil, fmt.Errorf("line %d: record without an owner name", line.number)
+		}
+
+		ttl := -1
+		for len(tokens) > 0 {
+			if ttl < 0 {
+				if v, err := parseZoneFileTTL(tokens[0]); err == nil {
+					ttl = v
+					tokens = tokens[1:]
+					continue
+				}
+			}
+			if class := strings.ToUpper(tokens[0]); class == "IN" || class == "CH" || class == "HS" {
+				tokens = tokens[1:]
+				continue
+			}
+			break
+		}
+		if len(tokens) < 2 {
+			return nil, fmt.Errorf("line %d: incomplete record", line.number)
+		}
+
+		if ttl < 0 {
+			ttl = defaultTTL
+		}
+		if ttl < 0 {
+			ttl = lastTTL
+		}
+		if ttl < 0 {
+			return nil, fmt.Errorf("line %d: record without a TTL and no $TTL directive", line.number)
+		}
+		lastTTL = ttl
+
+		recordtype := strings.ToUpper(tokens[0])
+		rdata := zoneFileRdata(recordtype, tokens[1:], origin)
+
+		name := strings.TrimSuffix(owner, ".")
+		key := recordsetKey(name, recordtype)
+		rs, ok := recordsets[key]
+		if !ok {
+			rs = &dnsv2.Recordset{Name: name, Type: recordtype, TTL: ttl}
+			recordsets[key] = rs
+			order = append(order, key)
+		}
+		rs.Rdata = append(rs.Rdata, rdata)
+	}
+
+	result := make([]dnsv2.Recordset, 0, len(order))
+	for _, key := range order {
+		result = append(result, normalizeRecordset(*recordsets[key]))
+	}
+	return result, nil
+}
+
+// zoneFileRecords renders recordsets as one sorted "name ttl type rdata" line
+// per record, with the SOA serial left out, for record by record comparison.
+func zoneFileRecords(recordsets []dnsv2.Recordset) []string {
+	var records []string
+	for _, rs := range recordsets {
+		for _, rdata := range comparableRdata(rs.Type, rs.Rdata) {
+			records = append(records, fmt.Sprintf("%s %d %s %s", rs.Name, rs.TTL, rs.Type, rdata))
+		}
+	}
+	sort.Strings(records)
+	return records
+}
+
+type zoneFileLine struct {
+	number  int
+	inherit bool
+	tokens  []string
+}
+
+// zoneFileLines splits a master file into logical lines of tokens, without
+// comments and with parenthesized continuations joined.
+func zoneFileLines(contents string) ([]zoneFileLine, error) {
+	var lines []zoneFileLine
+	var current *zoneFileLine
+	depth := 0
+
+	for i, text := range strings.Split(contents, "\n") {
+		text = strings.TrimRight(text, "\r")
+		tokens, opened, closed, err := zoneFileTokens(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+
+		if depth == 0 {
+			if len(tokens) == 0 && opened == 0 {
+				continue
+			}
+			lines = append(lines, zoneFileLine{
+				number:  i + 1,
+				inherit: len(text) > 0 && (text[0] == ' ' || text[0] == '\t'),
+			})
+			current = &lines[len(lines)-1]
+		}
+		current.tokens = append(current.tokens, tokens...)
+
+		depth += opened - closed
+		if depth < 0 {
+			return nil, fmt.Errorf("line %d: unbalanced parentheses", i+1)
+		}
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses at end of zone file")
+	}
+	return lines, nil
+}
+
+// zoneFileTokens splits a single line into tokens, keeping quoted strings
+// (with their quotes) together and dropping comments and parentheses.
+func zoneFileTokens(line string) (tokens []string, opened int, closed int, err error) {
+	var token strings.Builder
+	inQuote := false
+	escaped := false
+
+	flush := func() {
+		if token.Len() > 0 {
+			tokens = append(tokens, token.String())
+			token.Reset()
+		}
+	}
+
+	for _, c := range line {
+		if inQuote {
+			token.WriteRune(c)
+			if escaped {
+				escaped = false
+			} else if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inQuote = false
+			}
+			continue
+		}
+
+		switch c {
+		case ';':
+			flush()
+			return tokens, opened, closed, nil
+		case ' ', '\t':
+			flush()
+		case '(':
+			flush()
+			opened++
+		case ')':
+			flush()
+			closed++
+		case '"':
+			inQuote = true
+			token.WriteRune(c)
+		default:
+			token.WriteRune(c)
+		}
+	}
+
+	if inQuote {
+		return nil, 0, 0, fmt.Errorf("unterminated quoted string")
+	}
+	flush()
+	return tokens, opened, closed, nil
+}
+
+// zoneFileRdata joins the rdata tokens of a record, qualifying relative domain
+// names with the origin.
+func zoneFileRdata(recordtype string, tokens []string, origin string) string {
+	rdata := append([]string(nil), tokens...)
+
+	switch recordtype {
+	case RRTypeCname, RRTypeNs, RRTypePtr:
+		if len(rdata) == 1 {
+			rdata[0] = absoluteName(rdata[0], origin)
+		}
+	case RRTypeMx, RRTypeSrv, RRTypeAfsdb:
+		rdata[len(rdata)-1] = absoluteName(rdata[len(rdata)-1], origin)
+	case RRTypeSoa:
+		if len(rdata) == 7 {
+			rdata[0] = absoluteName(rdata[0], origin)
+			rdata[1] = absoluteName(rdata[1], origin)
+		}
+	}
+
+	return strings.Join(rdata, " ")
+}
+
+// absoluteName qualifies a relative domain name with the origin.
+func absoluteName(name string, origin string) string {
+	if name == "@" {
+		return origin
+	}
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "." + origin
+}
+
+// parseZoneFileTTL parses a TTL in seconds or with BIND unit suffixes, e.g. 1h30m.
+func parseZoneFileTTL(value string) (int, error) {
+	if v, err := strconv.Atoi(value); err == nil && v >= 0 {
+		return v, nil
+	}
+
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	total := 0
+	number := ""
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c >= '0' && c <= '9' {
+			number += string(c)
+			continue
+		}
+		unit, ok := units[c|0x20]
+		if !ok || number == "" {
+			return 0, fmt.Errorf("invalid TTL %q", value)
+		}
+		v, _ := strconv.Atoi(number)
+		total += v * unit
+		number = ""
+	}
+	if number != "" || total == 0 && value != "0" {
+		return 0, fmt.Errorf("invalid TTL %q", value)
+	}
+	return total, nil
+}
@@ -0,0 +1,93 @@
+package akamai
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testZoneFile = `$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	a1-2.akam.net. hostmaster ( 2019010101 ; serial
+			3600 600 604800 300 )
+	IN	NS	a1-2.akam.net.
+www	300	IN	A	10.0.0.3
+	300	IN	A	10.0.0.2 ; second address
+cdn		CNAME	www
+@	MX	10 mail
+txt	TXT	"v=spf1 -all; comment"
+`
+
+func TestParseZoneFile(t *testing.T) {
+	recordsets, err := parseZoneFile("example.com", testZoneFile)
+	if err != nil {
+		t.Fatalf("Zone file is invalid: %v", err)
+	}
+
+	expected := []string{
+		"cdn.example.com 3600 CNAME www.example.com.",
+		"example.com 3600 MX 10 mail.example.com.",
+		"example.com 3600 NS a1-2.akam.net.",
+		"example.com 3600 SOA a1-2.akam.net. hostmaster.example.com. 0 3600 600 604800 300",
+		"txt.example.com 3600 TXT \"v=spf1 -all; comment\"",
+		"www.example.com 300 A 10.0.0.2",
+		"www.example.com 300 A 10.0.0.3",
+	}
+	if records := zoneFileRecords(recordsets); !reflect.DeepEqual(records, expected) {
+		t.Errorf("Value %v is invalid: %v", records, expected)
+	}
+}
+
+func TestParseZoneFileErrors(t *testing.T) {
+	invalid := []string{
+		"www IN A 10.0.0.1\n",
+		"$TTL 300\nwww IN A (10.0.0.1\n",
+		"$TTL 300\n$INCLUDE other.zone\n",
+		"$TTL 300\ntxt IN TXT \"unterminated\n",
+		"$TTL 300\nwww IN\n",
+	}
+	for _, v := range invalid {
+		if _, err := parseZoneFile("example.com", v); err == nil {
+			t.Errorf("Value %q should be invalid", v)
+		}
+	}
+}
+
+func TestEquivalentZoneFiles(t *testing.T) {
+	recordsets, err := parseZoneFile("example.com", testZoneFile)
+	if err != nil {
+		t.Fatalf("Zone file is invalid: %v", err)
+	}
+	records := zoneFileRecords(recordsets)
+
+	reordered := `$TTL 3600
+example.com. IN SOA a1-2.akam.net. hostmaster.example.com. 2019010102 3600 600 604800 300
+example.com. IN NS a1-2.akam.net.
+WWW.example.com. 300 IN A 10.0.0.2
+www.example.com. 300 IN A 10.0.0.3
+cdn.example.com. IN CNAME www.example.com.
+example.com. IN MX 10 mail.example.com.
+txt.example.com. IN TXT "v=spf1 -all; comment"
+`
+	if !equivalentZoneFiles("example.com", reordered, records) {
+		t.Errorf("Value %q should be equivalent", reordered)
+	}
+
+	changed := reordered + "new.example.com. IN A 10.0.0.4\n"
+	if equivalentZoneFiles("example.com", changed, records) {
+		t.Errorf("Value %q should not be equivalent", changed)
+	}
+}
+
+func TestParseZoneFileTTL(t *testing.T) {
+	valid := map[string]int{"0": 0, "300": 300, "1h": 3600, "1h30m": 5400, "1W": 604800}
+	for v, expected := range valid {
+		if ttl, err := parseZoneFileTTL(v); err != nil || ttl != expected {
+			t.Errorf("Value %v is invalid: %v %v", v, ttl, err)
+		}
+	}
+	for _, v := range []string{"", "h", "10x", "1h5", "IN"} {
+		if _, err := parseZoneFileTTL(v); err == nil {
+			t.Errorf("Value %v should be invalid", v)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package akamai
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+)
+
+// Edge DNS Zones
+//
+// configdns-v2 only models primary and secondary zones without TSIG keys, so
+// zones are created, read and updated directly with the DNS credentials.
+
+// dnsZone is a zone as accepted and returned by the Edge DNS API.
+type dnsZone struct {
+	Zone                  string      `json:"zone"`
+	Type                  string      `json:"type"`
+	Masters               []string    `json:"masters,omitempty"`
+	Comment               string      `json:"comment,omitempty"`
+	SignAndServe          bool        `json:"signAndServe"`
+	SignAndServeAlgorithm string      `json:"signAndServeAlgorithm,omitempty"`
+	Target                string      `json:"target,omitempty"`
+	TsigKey               *dnsTSIGKey `json:"tsigKey,omitempty"`
+	EndCustomerID         string      `json:"endCustomerId,omitempty"`
+	ContractID            string      `json:"contractId,omitempty"`
+	ActivationState       string      `json:"activationState,omitempty"`
+	LastActivationDate    string      `json:"lastActivationDate,omitempty"`
+	LastModifiedBy        string      `json:"lastModifiedBy,omitempty"`
+	LastModifiedDate      string      `json:"lastModifiedDate,omitempty"`
+	VersionID             string      `json:"versionId,omitempty"`
+}
+
+// dnsTSIGKey authenticates zone transfers from the masters of a secondary zone.
+type dnsTSIGKey struct {
+	Name      string `json:"name"`
+	Algorithm string `json:"algorithm"`
+	Secret    string `json:"secret"`
+}
+
+// String returns the zone with its TSIG secret redacted, so zones can be logged.
+func (z dnsZone) String() string {
+	if z.TsigKey != nil {
+		key := *z.TsigKey
+		key.Secret = "<redacted>"
+		z.TsigKey = &key
+	}
+
+	b, err := json.Marshal(z)
+	if err != nil {
+		return z.Zone
+	}
+	return string(b)
+}
+
+// getZone returns the settings of a zone.
+//
+// Endpoint: GET /config-dns/v2/zones/{zone}
+func getZone(zone string) (*dnsZone, error) {
+	var z dnsZone
+	if err := dnsDo("GET", fmt.Sprintf("/config-dns/v2/zones/%s", url.PathEscape(zone)), nil, &z); err != nil {
+		return nil, err
+	}
+	return &z, nil
+}
+
+// createZone creates a zone in the contract and group of zonequerystring.
+//
+// Endpoint: POST /config-dns/v2/zones{?contractId,gid}
+func createZone(z *dnsZone, zonequerystring dnsv2.ZoneQueryString) error {
+	query := url.Values{}
+	query.Set("contractId", zonequerystring.Contract)
+	if zonequerystring.Group != "" {
+		query.Set("gid", zonequerystring.Group)
+	}
+	return dnsDo("POST", "/config-dns/v2/zones?"+query.Encode(), z, nil)
+}
+
+// updateZone replaces the settings of a zone.
+//
+// Endpoint: PUT /config-dns/v2/zones/{zone}
+func updateZone(z *dnsZone) error {
+	return dnsDo("PUT", fmt.Sprintf("/config-dns/v2/zones/%s", url.PathEscape(z.Zone)), z, nil)
+}
+
+// dnsSecStatus is the DNSSEC state of a signed zone. NewRecords is only set
+// while a key rollover is in progress.
+type dnsSecStatus struct {
+	Zone           string         `json:"zone"`
+	Alerts         []string       `json:"alerts"`
+	CurrentRecords dnsSecRecords  `json:"currentRecords"`
+	NewRecords     *dnsSecRecords `json:"newRecords,omitempty"`
+}
+
+type dnsSecRecords struct {
+	DnskeyRecord     string `json:"dnskeyRecord"`
+	DsRecord         string `json:"dsRecord"`
+	ExpectedTTL      int    `json:"expectedTtl"`
+	LastModifiedDate string `json:"lastModifiedDate"`
+}
+
+// getZoneDNSSecStatus returns the DNSKEY and DS records of a signed zone.
+//
+// Endpoint: POST /config-dns/v2/zones/dns-sec-status
+func getZoneDNSSecStatus(zone string) (*dnsSecStatus, error) {
+	var res struct {
+		DNSSecStatuses []dnsSecStatus `json:"dnsSecStatuses"`
+	}
+	body := map[string]interface{}{"zones": []string{zone}}
+	if err := dnsDo("POST", "/config-dns/v2/zones/dns-sec-status", body, &res); err != nil {
+		return nil, err
+	}
+
+	for _, status := range res.DNSSecStatuses {
+		if strings.EqualFold(strings.TrimSuffix(status.Zone, "."), strings.TrimSuffix(zone, ".")) {
+			return &status, nil
+		}
+	}
+	return nil, fmt.Errorf("no DNSSEC status returned for zone %s, check that sign_and_serve is enabled", zone)
+}
+
+// rotateZoneKeys starts a rollover of the signing keys of a zone. The new keys
+// are reported as NewRecords by getZoneDNSSecStatus until the rollover ends.
+//
+// Endpoint: POST /config-dns/v2/zones/{zone}/key-rotation
+func rotateZoneKeys(zone string) error {
+	return dnsDo("POST", fmt.Sprintf("/config-dns/v2/zones/%s/key-rotation", url.PathEscape(zone)), nil, nil)
+}
+
+type zoneDeleteRequest struct {
+	RequestID      string `json:"requestId"`
+	ExpirationDate string `json:"expirationDate"`
+}
+
+type zoneDeleteStatus struct {
+	RequestID      string `json:"requestId"`
+	ZonesSubmitted int    `json:"zonesSubmitted"`
+	SuccessCount   int    `json:"successCount"`
+	FailureCount   int    `json:"failureCount"`
+	IsComplete     bool   `json:"isComplete"`
+	ExpirationDate string `json:"expirationDate"`
+}
+
+type zoneDeleteResult struct {
+	RequestID                string   `json:"requestId"`
+	SuccessfullyDeletedZones []string `json:"successfullyDeletedZones"`
+	FailedZones              []struct {
+		Zone          string `json:"zone"`
+		FailureReason string `json:"failureReason"`
+	} `json:"failedZones"`
+}
+
+// deleteZone deletes a zone with a bulk delete request and waits for the
+// request to complete. Edge DNS refuses to delete zones that still have
+// records unless bypassSafetyChecks is set.
+//
+// Endpoint: POST /config-dns/v2/zones/delete-requests{?bypassSafetyChecks}
+func deleteZone(zone string, bypassSafetyChecks bool) error {
+	var request zoneDeleteRequest
+	body := map[string]interface{}{"zones": []string{zone}}
+	path := fmt.Sprintf("/config-dns/v2/zones/delete-requests?bypassSafetyChecks=%t", bypassSafetyChecks)
+	if err := dnsDo("POST", path, body, &request); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Delete request [%s] submitted for zone [%s]", request.RequestID, zone)
+	if err := waitForZoneDelete(request.RequestID); err != nil {
+		return err
+	}
+
+	var result zoneDeleteResult
+	if err := dnsDo("GET", fmt.Sprintf("/config-dns/v2/zones/delete-requests/%s/result", url.PathEscape(request.RequestID)), nil, &result); err != nil {
+		return err
+	}
+	for _, failed := range result.FailedZones {
+		if strings.EqualFold(strings.TrimSuffix(failed.Zone, "."), strings.TrimSuffix(zone, ".")) {
+			return fmt.Errorf("unable to delete zone %s: %s", zone, failed.FailureReason)
+		}
+	}
+	return nil
+}
+
+// waitForZoneDelete polls a bulk delete request until it completes.
+//
+// Endpoint: GET /config-dns/v2/zones/delete-requests/{requestId}
+func waitForZoneDelete(requestID string) error {
+	var sleepInterval time.Duration = 5 * time.Second
+	var sleepTimeout time.Duration = 300 * time.Second
+
+	for {
+		var status zoneDeleteStatus
+		if err := dnsDo("GET", fmt.Sprintf("/config-dns/v2/zones/delete-requests/%s", url.PathEscape(requestID)), nil, &status); err != nil {
+			return err
+		}
+		log.Printf("[DEBUG] [Akamai DNSv2] WAIT: Delete request [%s] complete [%v]", requestID, status.IsComplete)
+		if status.IsComplete {
+			return nil
+		}
+
+		if sleepTimeout <= 0 {
+			return fmt.Errorf("timed out waiting for delete request %s to complete", requestID)
+		}
+		time.Sleep(sleepInterval)
+		sleepTimeout -= sleepInterval
+	}
+}
+
+const zoneListPageSize = 1000
+
+type zoneList struct {
+	Metadata struct {
+		Page          int `json:"page"`
+		PageSize      int `json:"pageSize"`
+		TotalElements int `json:"totalElements"`
+	} `json:"metadata"`
+	Zones []dnsZone `json:"zones"`
+}
+
+// listZones returns the zones of a contract, or of every contract the
+// credentials can access when contract is empty. search and zonetypes narrow
+// the list on the server.
+//
+// Endpoint: GET /config-dns/v2/zones{?contractIds,types,search,page,pageSize}
+func listZones(contract string, zonetypes []string, search string) ([]dnsZone, error) {
+	query := url.Values{}
+	if contract != "" {
+		query.Set("contractIds", contract)
+	}
+	if len(zonetypes) > 0 {
+		query.Set("types", strings.Join(zonetypes, ","))
+	}
+	if search != "" {
+		query.Set("search", search)
+	}
+	query.Set("pageSize", fmt.Sprintf("%d", zoneListPageSize))
+
+	var zones []dnsZone
+	for page := 1; ; page++ {
+		query.Set("page", fmt.Sprintf("%d", page))
+
+		var res zoneList
+		if err := dnsDo("GET", "/config-dns/v2/zones?"+query.Encode(), nil, &res); err != nil {
+			return nil, err
+		}
+
+		zones = append(zones, res.Zones...)
+		if len(res.Zones) == 0 || len(zones) >= res.Metadata.TotalElements {
+			break
+		}
+	}
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Listed %d zones", len(zones))
+	return zones, nil
+}
@@ -0,0 +1,393 @@
+package akamai
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+)
+
+// PAPI Includes
+//
+// Includes are versioned rule fragments that properties reference through the
+// include behavior. papi-v1 does not wrap the includes endpoints, so they are
+// called directly with the property credentials and reuse the papi-v1 rule,
+// network and status types.
+//
+// https://developer.akamai.com/api/core_features/property_manager/v1.html#includes
+
+type propertyInclude struct {
+	IncludeID         string `json:"includeId,omitempty"`
+	IncludeName       string `json:"includeName"`
+	IncludeType       string `json:"includeType"`
+	ProductID         string `json:"productId,omitempty"`
+	RuleFormat        string `json:"ruleFormat,omitempty"`
+	ContractID        string `json:"contractId,omitempty"`
+	GroupID           string `json:"groupId,omitempty"`
+	LatestVersion     int    `json:"latestVersion,omitempty"`
+	StagingVersion    *int   `json:"stagingVersion,omitempty"`
+	ProductionVersion *int   `json:"productionVersion,omitempty"`
+}
+
+type propertyIncludes struct {
+	Includes struct {
+		Items []*propertyInclude `json:"items"`
+	} `json:"includes"`
+}
+
+type propertyIncludeVersion struct {
+	IncludeVersion   int              `json:"includeVersion"`
+	StagingStatus    papi.StatusValue `json:"stagingStatus"`
+	ProductionStatus papi.StatusValue `json:"productionStatus"`
+}
+
+type propertyIncludeVersions struct {
+	Versions struct {
+		Items []*propertyIncludeVersion `json:"items"`
+	} `json:"versions"`
+}
+
+type propertyIncludeRules struct {
+	RuleFormat string             `json:"ruleFormat,omitempty"`
+	Rule       *papi.Rule         `json:"rules"`
+	Errors     []*papi.RuleErrors `json:"errors,omitempty"`
+}
+
+type propertyIncludeActivation struct {
+	ActivationID           string               `json:"activationId,omitempty"`
+	ActivationType         papi.ActivationValue `json:"activationType"`
+	IncludeVersion         int                  `json:"includeVersion"`
+	Network                papi.NetworkValue    `json:"network"`
+	Note                   string               `json:"note,omitempty"`
+	NotifyEmails           []string             `json:"notifyEmails"`
+	AcknowledgeAllWarnings bool                 `json:"acknowledgeAllWarnings,omitempty"`
+	Status                 papi.StatusValue     `json:"status,omitempty"`
+}
+
+type propertyIncludeActivations struct {
+	Activations struct {
+		Items []*propertyIncludeActivation `json:"items"`
+	} `json:"activations"`
+}
+
+func includeQuery(contractID, groupID string) string {
+	return fmt.Sprintf("contractId=%s&groupId=%s", url.QueryEscape(contractID), url.QueryEscape(groupID))
+}
+
+// linkID returns the last path segment of a PAPI link, without its query string.
+func linkID(link string) string {
+	link = strings.SplitN(link, "?", 2)[0]
+	return link[strings.LastIndex(link, "/")+1:]
+}
+
+// Endpoint: POST /papi/v1/includes{?contractId,groupId}
+func createPropertyInclude(include *propertyInclude, contractID, groupID string) (string, error) {
+	var res struct {
+		IncludeLink string `json:"includeLink"`
+	}
+	if err := papiDo("POST", "/papi/v1/includes?"+includeQuery(contractID, groupID), include, &res); err != nil {
+		return "", err
+	}
+	return linkID(res.IncludeLink), nil
+}
+
+// Endpoint: GET /papi/v1/includes/{includeId}{?contractId,groupId}
+func getPropertyInclude(includeID, contractID, groupID string) (*propertyInclude, error) {
+	var res propertyIncludes
+	if err := papiDo("GET", fmt.Sprintf("/papi/v1/includes/%s?%s", includeID, includeQuery(contractID, groupID)), nil, &res); err != nil {
+		return nil, err
+	}
+	if len(res.Includes.Items) == 0 {
+		return nil, fmt.Errorf("include %s not found", includeID)
+	}
+	return res.Includes.Items[0], nil
+}
+
+// Endpoint: GET /papi/v1/includes{?contractId,groupId}
+func listPropertyIncludes(contractID, groupID string) ([]*propertyInclude, error) {
+	var res propertyIncludes
+	if err := papiDo("GET", "/papi/v1/includes?"+includeQuery(contractID, groupID), nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Includes.Items, nil
+}
+
+// Endpoint: GET /papi/v1/includes{?contractId,groupId}
+func findPropertyInclude(name, contractID, groupID string) (*propertyInclude, error) {
+	includes, err := listPropertyIncludes(contractID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	for _, include := range includes {
+		if include.IncludeName == name {
+			return include, nil
+		}
+	}
+	return nil, nil
+}
+
+// Endpoint: DELETE /papi/v1/includes/{includeId}{?contractId,groupId}
+func deletePropertyInclude(includeID, contractID, groupID string) error {
+	return papiDo("DELETE", fmt.Sprintf("/papi/v1/includes/%s?%s", includeID, includeQuery(contractID, groupID)), nil, nil)
+}
+
+// Endpoint: GET /papi/v1/includes/{includeId}/versions/{includeVersion}{?contractId,groupId}
+func getPropertyIncludeVersion(includeID string, version int, contractID, groupID string) (*propertyIncludeVersion, error) {
+	var res propertyIncludeVersions
+	if err := papiDo("GET", fmt.Sprintf("/papi/v1/includes/%s/versions/%d?%s", includeID, version, includeQuery(contractID, groupID)), nil, &res); err != nil {
+		return nil, err
+	}
+	if len(res.Versions.Items) == 0 {
+		return nil, fmt.Errorf("include %s version %d not found", includeID, version)
+	}
+	return res.Versions.Items[0], nil
+}
+
+// ensureEditableIncludeVersion returns a version of the include that has never
+// been activated, creating one from the latest version when needed, the same
+// way ensureEditableVersion does for properties.
+//
+// Endpoint: POST /papi/v1/includes/{includeId}/versions{?contractId,groupId}
+func ensureEditableIncludeVersion(include *propertyInclude, contractID, groupID string) (int, error) {
+	latest, err := getPropertyIncludeVersion(include.IncludeID, include.LatestVersion, contractID, groupID)
+	if err != nil {
+		return 0, err
+	}
+
+	if latest.ProductionStatus == papi.StatusInactive && latest.StagingStatus == papi.StatusInactive {
+		return latest.IncludeVersion, nil
+	}
+
+	var res struct {
+		VersionLink string `json:"versionLink"`
+	}
+	body := map[string]interface{}{"createFromVersion": latest.IncludeVersion}
+	if err := papiDo("POST", fmt.Sprintf("/papi/v1/includes/%s/versions?%s", include.IncludeID, includeQuery(contractID, groupID)), body, &res); err != nil {
+		return 0, err
+	}
+
+	include, err = getPropertyInclude(include.IncludeID, contractID, groupID)
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("[DEBUG] Created include %s version %d", include.IncludeID, include.LatestVersion)
+	return include.LatestVersion, nil
+}
+
+// Endpoint: GET /papi/v1/includes/{includeId}/versions/{includeVersion}/rules{?contractId,groupId}
+func getPropertyIncludeRules(includeID string, version int, contractID, groupID string) (*propertyIncludeRules, error) {
+	rules := &propertyIncludeRules{Rule: papi.NewRule()}
+	if err := papiDo("GET", fmt.Sprintf("/papi/v1/includes/%s/versions/%d/rules?%s", includeID, version, includeQuery(contractID, groupID)), nil, rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Endpoint: PUT /papi/v1/includes/{includeId}/versions/{includeVersion}/rules{?contractId,groupId}
+func savePropertyIncludeRules(includeID string, version int, contractID, groupID string, rules *propertyIncludeRules) error {
+	if err := papiDo("PUT", fmt.Sprintf("/papi/v1/includes/%s/versions/%d/rules?%s", includeID, version, includeQuery(contractID, groupID)), rules, rules); err != nil {
+		return err
+	}
+
+	if len(rules.Errors) > 0 {
+		var msg string
+		for _, v := range rules.Errors {
+			msg = msg + fmt.Sprintf("\n Rule validation error: %s %s %s %s %s", v.Type, v.Title, v.Detail, v.Instance, v.BehaviorName)
+		}
+		return fmt.Errorf("Error - Invalid Include Rules%s", msg)
+	}
+	return nil
+}
+
+// Endpoint: POST /papi/v1/includes/{includeId}/activations{?contractId,groupId}
+func createPropertyIncludeActivation(includeID, contractID, groupID string, activation *propertyIncludeActivation) error {
+	var res struct {
+		ActivationLink string `json:"activationLink"`
+	}
+	if err := papiDo("POST", fmt.Sprintf("/papi/v1/includes/%s/activations?%s", includeID, includeQuery(contractID, groupID)), activation, &res); err != nil {
+		return err
+	}
+	activation.ActivationID = linkID(res.ActivationLink)
+	return nil
+}
+
+// Endpoint: GET /papi/v1/includes/{includeId}/activations{?contractId,groupId}
+func getPropertyIncludeActivations(includeID, contractID, groupID string) ([]*propertyIncludeActivation, error) {
+	var res propertyIncludeActivations
+	if err := papiDo("GET", fmt.Sprintf("/papi/v1/includes/%s/activations?%s", includeID, includeQuery(contractID, groupID)), nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Activations.Items, nil
+}
+
+// Endpoint: GET /papi/v1/includes/{includeId}/activations/{activationId}{?contractId,groupId}
+func getPropertyIncludeActivation(includeID, activationID, contractID, groupID string) (*propertyIncludeActivation, error) {
+	var res propertyIncludeActivations
+	if err := papiDo("GET", fmt.Sprintf("/papi/v1/includes/%s/activations/%s?%s", includeID, activationID, includeQuery(contractID, groupID)), nil, &res); err != nil {
+		return nil, err
+	}
+	if len(res.Activations.Items) == 0 {
+		return nil, fmt.Errorf("include activation %s not found", activationID)
+	}
+	return res.Activations.Items[0], nil
+}
+
+// pollIncludeActivation polls an include activation until it reaches the final
+// status, reporting each status change on statusChange like
+// papi.Activation.PollStatus does for property activations, and false when
+// the activation fails or cannot be read.
+func pollIncludeActivation(includeID, contractID, groupID string, activation *propertyIncludeActivation, final papi.StatusValue, statusChange chan bool) {
+	retry := time.Duration(0)
+	for activation.Status != final {
+		time.Sleep(retry)
+
+		a, err := getPropertyIncludeActivation(includeID, activation.ActivationID, contractID, groupID)
+		if err != nil {
+			log.Printf("[WARN] Unable to read include %s activation %s: %s", includeID, activation.ActivationID, err)
+			statusChange <- false
+			return
+		}
+		if a.Status == papi.StatusFailed || a.Status == papi.StatusAborted {
+			activation.Status = a.Status
+			statusChange <- false
+			return
+		}
+		if a.Status != activation.Status {
+			activation.Status = a.Status
+			statusChange <- true
+		}
+
+		retry = time.Minute
+		if activation.Network == papi.NetworkStaging {
+			retry = time.Second * 30
+		}
+	}
+}
+
+// resolveIncludeBehaviors rewrites include behaviors that reference an include
+// by name into the include ID expected by PAPI.
+func resolveIncludeBehaviors(rule *papi.Rule, contractID, groupID string) error {
+	for _, behavior := range rule.Behaviors {
+		if behavior.Name != "include" {
+			continue
+		}
+
+		if id, ok := behavior.Options["id"].(string); ok && strings.HasPrefix(id, "inc_") {
+			behavior.Options = papi.OptionValue{"id": id}
+			continue
+		}
+
+		name, ok := behavior.Options["name"].(string)
+		if !ok {
+			name, ok = behavior.Options["id"].(string)
+		}
+		if !ok || name == "" {
+			return fmt.Errorf("include behavior in rule %q must set an id or name", rule.Name)
+		}
+
+		include, err := findPropertyInclude(name, contractID, groupID)
+		if err != nil {
+			return err
+		}
+		if include == nil {
+			return fmt.Errorf("include %q not found", name)
+		}
+
+		log.Printf("[DEBUG] Resolved include %s to %s", name, include.IncludeID)
+		behavior.Options = papi.OptionValue{"id": include.IncludeID}
+	}
+
+	for _, child := range rule.Children {
+		if err := resolveIncludeBehaviors(child, contractID, groupID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nameIncludeBehaviors adds the include name to include behaviors read from
+// PAPI, which only carry the include ID. Rules in state then hold both, so
+// configurations referencing includes by name compare without API calls.
+func nameIncludeBehaviors(rule *papi.Rule, contractID, groupID string) error {
+	if !hasIncludeBehaviors(rule) {
+		return nil
+	}
+
+	includes, err := listPropertyIncludes(contractID, groupID)
+	if err != nil {
+		return err
+	}
+	names := make(map[string]string, len(includes))
+	for _, include := range includes {
+		names[include.IncludeID] = include.IncludeName
+	}
+
+	walkIncludeBehaviors(rule, func(behavior *papi.Behavior) {
+		if id, ok := behavior.Options["id"].(string); ok && names[id] != "" {
+			behavior.Options = papi.OptionValue{"id": id, "name": names[id]}
+		}
+	})
+	return nil
+}
+
+// canonicalIncludeBehaviors reduces include behaviors to the include name,
+// using the ID to name pairs found in rules, so a reference by ID and one by
+// name to the same include compare equal.
+func canonicalIncludeBehaviors(rules ...*papi.Rule) {
+	names := map[string]string{}
+	for _, rule := range rules {
+		includeBehaviorNames(rule, names)
+	}
+
+	for _, rule := range rules {
+		walkIncludeBehaviors(rule, func(behavior *papi.Behavior) {
+			name, _ := behavior.Options["name"].(string)
+			if id, ok := behavior.Options["id"].(string); ok && name == "" {
+				name = names[id]
+				if name == "" && !strings.HasPrefix(id, "inc_") {
+					name = id
+				}
+			}
+			if name != "" {
+				behavior.Options = papi.OptionValue{"name": name}
+			}
+		})
+	}
+}
+
+// includeBehaviorNames collects the ID to name pairs of include behaviors
+// that carry both.
+func includeBehaviorNames(rule *papi.Rule, names map[string]string) map[string]string {
+	walkIncludeBehaviors(rule, func(behavior *papi.Behavior) {
+		id, _ := behavior.Options["id"].(string)
+		name, _ := behavior.Options["name"].(string)
+		if id != "" && name != "" {
+			names[id] = name
+		}
+	})
+	return names
+}
+
+func hasIncludeBehaviors(rule *papi.Rule) bool {
+	found := false
+	walkIncludeBehaviors(rule, func(*papi.Behavior) { found = true })
+	return found
+}
+
+func walkIncludeBehaviors(rule *papi.Rule, fn func(*papi.Behavior)) {
+	if rule == nil {
+		return
+	}
+	for _, behavior := range rule.Behaviors {
+		if behavior.Name == "include" {
+			fn(behavior)
+		}
+	}
+	for _, child := range rule.Children {
+		walkIncludeBehaviors(child, fn)
+	}
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
 	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
 	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/configgtm-v1_4"
+	cps "github.com/akamai/AkamaiOPEN-edgegrid-golang/cps-v2"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+	reportsgtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/reportsgtm-v1"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -20,8 +22,11 @@ const (
 	Version = "0.2.0"
 )
 
-// Config contains the Akamai provider configuration (unused).
+// Config contains the Akamai provider configuration.
 type Config struct {
+	// cps is set when CPS credentials are configured. Certificate
+	// enrollments can only be checked at plan time with them.
+	cps bool
 }
 
 func getConfigOptions(section string) *schema.Resource {
@@ -118,6 +123,11 @@ func Provider() terraform.ResourceProvider {
 				Type:     schema.TypeString,
 				Default:  "default",
 			},
+			"cps_section": &schema.Schema{
+				Optional: true,
+				Type:     schema.TypeString,
+				Default:  "default",
+			},
 			"papi_section": &schema.Schema{
 				Optional: true,
 				Type:     schema.TypeString,
@@ -143,33 +153,50 @@ func Provider() terraform.ResourceProvider {
 				Type:     schema.TypeSet,
 				Elem:     getConfigOptions("gtm"),
 			},
+			"cps": &schema.Schema{
+				Optional: true,
+				Type:     schema.TypeSet,
+				Elem:     getConfigOptions("cps"),
+			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"akamai_authorities_set":        dataSourceAuthoritiesSet(),
 			"akamai_contract":               dataSourcePropertyContract(),
 			"akamai_cp_code":                dataSourceCPCode(),
 			"akamai_dns_record_set":         dataSourceDNSRecordSet(),
+			"akamai_dns_zone_file":          dataSourceDNSZoneFile(),
+			"akamai_dns_zone_recordsets":    dataSourceDNSZoneRecordsets(),
+			"akamai_dns_zones":              dataSourceDNSZones(),
+			"akamai_dns_zone_dnssec":        dataSourceDNSZoneDNSSec(),
 			"akamai_group":                  dataSourcePropertyGroups(),
 			"akamai_property_rules":         dataPropertyRules(),
 			"akamai_property":               dataSourceAkamaiProperty(),
 			"akamai_gtm_default_datacenter": dataSourceGTMDefaultDatacenter(),
+			"akamai_gtm_property_liveness":  dataSourceGTMPropertyLiveness(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"akamai_cp_code":             resourceCPCode(),
-			"akamai_dns_zone":            resourceDNSv2Zone(),
-			"akamai_dns_record":          resourceDNSv2Record(),
-			"akamai_edge_hostname":       resourceSecureEdgeHostName(),
-			"akamai_property":            resourceProperty(),
-			"akamai_property_rules":      resourcePropertyRules(),
-			"akamai_property_variables":  resourcePropertyVariables(),
-			"akamai_property_activation": resourcePropertyActivation(),
-			"akamai_gtm_domain":          resourceGTMv1Domain(),
-			"akamai_gtm_datacenter":      resourceGTMv1Datacenter(),
-			"akamai_gtm_property":        resourceGTMv1Property(),
-			"akamai_gtm_resource":        resourceGTMv1Resource(),
-			"akamai_gtm_cidrmap":         resourceGTMv1Cidrmap(),
-			"akamai_gtm_geomap":          resourceGTMv1Geomap(),
-			"akamai_gtm_asmap":           resourceGTMv1ASmap(),
+			"akamai_cp_code":                     resourceCPCode(),
+			"akamai_dns_zone":                    resourceDNSv2Zone(),
+			"akamai_dns_record":                  resourceDNSv2Record(),
+			"akamai_dns_zone_records":            resourceDNSv2ZoneRecords(),
+			"akamai_dns_failover_record":         resourceDNSv2FailoverRecord(),
+			"akamai_dns_changelist":              resourceDNSv2Changelist(),
+			"akamai_edge_hostname":               resourceSecureEdgeHostName(),
+			"akamai_property":                    resourceProperty(),
+			"akamai_property_rules":              resourcePropertyRules(),
+			"akamai_property_variables":          resourcePropertyVariables(),
+			"akamai_property_activation":         resourcePropertyActivation(),
+			"akamai_property_include":            resourcePropertyInclude(),
+			"akamai_property_include_activation": resourcePropertyIncludeActivation(),
+			"akamai_gtm_domain":                  resourceGTMv1Domain(),
+			"akamai_gtm_datacenter":              resourceGTMv1Datacenter(),
+			"akamai_gtm_property":                resourceGTMv1Property(),
+			"akamai_gtm_resource":                resourceGTMv1Resource(),
+			"akamai_gtm_cidrmap":                 resourceGTMv1Cidrmap(),
+			"akamai_gtm_geomap":                  resourceGTMv1Geomap(),
+			"akamai_gtm_asmap":                   resourceGTMv1ASmap(),
+			"akamai_gtm_property_traffic_target": resourceGTMv1PropertyTrafficTarget(),
+			"akamai_gtm_weight_shift":            resourceGTMv1WeightShift(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
@@ -179,12 +206,13 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	dnsv2Config, dnsErr := getConfigDNSV2Service(d)
 	papiConfig, papiErr := getPAPIV1Service(d)
 	gtmConfig, gtmErr := getConfigGTMV1Service(d)
+	cpsConfig, cpsErr := getCPSV2Service(d)
 
-	if dnsErr != nil && papiErr != nil && gtmErr != nil || dnsv2Config == nil && papiConfig == nil && gtmConfig == nil {
+	if dnsErr != nil && papiErr != nil && gtmErr != nil && cpsErr != nil || dnsv2Config == nil && papiConfig == nil && gtmConfig == nil && cpsConfig == nil {
 		return nil, fmt.Errorf("at least one configuration must be defined")
 	}
 
-	return &Config{}, nil
+	return &Config{cps: cpsErr == nil && cpsConfig != nil}, nil
 }
 
 type resourceData interface {
@@ -240,6 +268,7 @@ func getConfigGTMV1Service(d resourceData) (*edgegrid.Config, error) {
 		}
 
 		gtm.Init(GTMv1Config)
+		reportsgtm.Init(GTMv1Config)
 		return &GTMv1Config, nil
 	}
 
@@ -251,9 +280,39 @@ func getConfigGTMV1Service(d resourceData) (*edgegrid.Config, error) {
 	}
 
 	gtm.Init(GTMv1Config)
+	reportsgtm.Init(GTMv1Config)
 	return &GTMv1Config, nil
 }
 
+func getCPSV2Service(d resourceData) (*edgegrid.Config, error) {
+	var CPSv2Config edgegrid.Config
+	var err error
+	if _, ok := d.GetOk("cps"); ok {
+		config := d.Get("cps").(set).List()[0].(map[string]interface{})
+
+		CPSv2Config = edgegrid.Config{
+			Host:         config["host"].(string),
+			AccessToken:  config["access_token"].(string),
+			ClientToken:  config["client_token"].(string),
+			ClientSecret: config["client_secret"].(string),
+			MaxBody:      config["max_body"].(int),
+		}
+
+		cps.Init(CPSv2Config)
+		return &CPSv2Config, nil
+	}
+
+	edgerc := d.Get("edgerc").(string)
+	section := d.Get("cps_section").(string)
+	CPSv2Config, err = edgegrid.Init(edgerc, section)
+	if err != nil {
+		return nil, err
+	}
+
+	cps.Init(CPSv2Config)
+	return &CPSv2Config, nil
+}
+
 func getPAPIV1Service(d resourceData) (*edgegrid.Config, error) {
 	var papiConfig edgegrid.Config
 	if _, ok := d.GetOk("property"); ok {
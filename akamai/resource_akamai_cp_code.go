@@ -1,7 +1,11 @@
 package akamai
 
 import (
+	"fmt"
 	"log"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
 
@@ -17,13 +21,16 @@ func resourceCPCode() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceCPCodeCreate,
 		Read:   resourceCPCodeRead,
+		Update: resourceCPCodeUpdate,
 		Delete: resourceCPCodeDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceCPCodeImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 			"contract": &schema.Schema{
 				Type:     schema.TypeString,
@@ -40,6 +47,24 @@ func resourceCPCode() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+			"reporting_group": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"prevent_destroy": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"product_ids": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"created_date": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -58,7 +83,9 @@ func resourceCPCodeCreate(d *schema.ResourceData, meta interface{}) error {
 		err := cpCode.Save()
 		if err != nil {
 			log.Print("[DEBUG] Error saving")
-			log.Printf("%s", err.(client.APIError).RawBody)
+			if apiErr, ok := err.(client.APIError); ok {
+				log.Printf("%s", apiErr.RawBody)
+			}
 			return err
 		}
 	}
@@ -66,6 +93,43 @@ func resourceCPCodeCreate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Resulting CP Code: %#v\n\n\n", cpCode)
 	d.SetId(cpCode.CpcodeID)
 
+	if reportingGroup, ok := d.GetOk("reporting_group"); ok {
+		if err := setReportingGroupCPCode(reportingGroup.(int), d.Get("contract").(string), cpCode.CpcodeID, true); err != nil {
+			return err
+		}
+	}
+
+	return resourceCPCodeRead(d, meta)
+}
+
+func resourceCPCodeUpdate(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[DEBUG] Updating CP Code")
+	d.Partial(true)
+
+	if d.HasChange("name") {
+		if err := renameCPCode(d.Id(), d.Get("name").(string)); err != nil {
+			return err
+		}
+		d.SetPartial("name")
+	}
+
+	if d.HasChange("reporting_group") {
+		contract := d.Get("contract").(string)
+		old, new := d.GetChange("reporting_group")
+		if old.(int) != 0 {
+			if err := setReportingGroupCPCode(old.(int), contract, d.Id(), false); err != nil {
+				return err
+			}
+		}
+		if new.(int) != 0 {
+			if err := setReportingGroupCPCode(new.(int), contract, d.Id(), true); err != nil {
+				return err
+			}
+		}
+		d.SetPartial("reporting_group")
+	}
+
+	d.Partial(false)
 	return resourceCPCodeRead(d, meta)
 }
 
@@ -74,26 +138,99 @@ func resourceCPCodeDelete(d *schema.ResourceData, meta interface{}) error {
 
 	// No PAPI CP Code delete operation exists.
 	// https://developer.akamai.com/api/luna/papi/resources.html#cpcodesapi
-	return schema.Noop(d, meta)
+	if d.Get("prevent_destroy").(bool) {
+		return fmt.Errorf("CP code %s has prevent_destroy set and cannot be removed", d.Id())
+	}
+
+	log.Printf("[WARN] CP codes cannot be deleted, CP code %s has only been removed from state", d.Id())
+	d.SetId("")
+	return nil
+}
+
+func resourceCPCodeImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	cpCodeID := d.Id()
+	if !strings.HasPrefix(cpCodeID, "cpc_") {
+		cpCodeID = "cpc_" + cpCodeID
+	}
+
+	groups := papi.NewGroups()
+	if err := groups.GetGroups(); err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups.Groups.Items {
+		for _, contractID := range group.ContractIDs {
+			cpCode := papi.NewCpCodes(&papi.Contract{ContractID: contractID}, group).NewCpCode()
+			cpCode.CpcodeID = cpCodeID
+			if err := cpCode.GetCpCode(); err != nil {
+				if isCPCodeNotInContract(err) {
+					continue
+				}
+				return nil, fmt.Errorf("unable to look up CP code %s in %s %s: %s", cpCodeID, contractID, group.GroupID, err)
+			}
+
+			log.Printf("[DEBUG] Importing CP code %s from %s %s", cpCodeID, contractID, group.GroupID)
+			d.Set("contract", contractID)
+			d.Set("group", group.GroupID)
+			if len(cpCode.ProductIDs) > 0 {
+				d.Set("product", cpCode.ProductIDs[0])
+			}
+			d.Set("prevent_destroy", false)
+			d.SetId(cpCode.CpcodeID)
+
+			return []*schema.ResourceData{d}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("CP code %s not found in any accessible contract and group", cpCodeID)
+}
+
+// isCPCodeNotInContract reports whether looking up a CP code failed because
+// it does not belong to the contract and group. PAPI answers 403 or 400 for
+// contracts the CP code is not in, and 404 for groups it is not in.
+func isCPCodeNotInContract(err error) bool {
+	apiErr, ok := err.(client.APIError)
+	if !ok {
+		return false
+	}
+	switch apiErr.Status {
+	case http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound:
+		return true
+	}
+	return false
 }
 
 func resourceCPCodeRead(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Reading CP Code")
 
-	cpCodes := resourceCPCodePAPINewCPCodes(d, meta)
-	cpCode, err := cpCodes.FindCpCode(d.Id())
-	if cpCode == nil || err != nil {
-		cpCode, err = cpCodes.FindCpCode(d.Get("name").(string))
-		if err != nil {
-			return err
+	cpCode := resourceCPCodePAPINewCPCodes(d, meta).NewCpCode()
+	cpCode.CpcodeID = d.Id()
+	if err := cpCode.GetCpCode(); err != nil {
+		if apiErr, ok := err.(client.APIError); ok && apiErr.Status == 404 {
+			log.Printf("[WARN] CP code %s not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
 		}
+		return err
 	}
 
-	if cpCode == nil {
-		return nil
+	d.SetId(cpCode.CpcodeID)
+	d.Set("name", cpCode.CpcodeName)
+	d.Set("product_ids", cpCode.ProductIDs)
+	if !cpCode.CreatedDate.IsZero() {
+		d.Set("created_date", cpCode.CreatedDate.Format(time.RFC3339))
+	}
+
+	if reportingGroup, ok := d.GetOk("reporting_group"); ok {
+		member, err := reportingGroupHasCPCode(reportingGroup.(int), cpCode.CpcodeID)
+		if err != nil {
+			return err
+		}
+		if !member {
+			d.Set("reporting_group", 0)
+		}
 	}
 
-	d.SetId(cpCode.CpcodeID)
 	log.Printf("[DEBUG] Read CP Code: %+v", cpCode)
 	return nil
 }
@@ -22,10 +22,43 @@ func TestAccAkamaiCPCode_basic(t *testing.T) {
 					resource.TestCheckResourceAttrSet(dataSourceName, "id"),
 				),
 			},
+			{
+				Config: testAccAkamaiCpCodeConfigRename(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "name", "terraform-testing-renamed"),
+				),
+			},
+			{
+				ResourceName:            dataSourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"prevent_destroy"},
+			},
 		},
 	})
 }
 
+func testAccAkamaiCpCodeConfigRename() string {
+	return `
+provider "akamai" {
+  papi_section = "papi"
+}
+
+data "akamai_contract" "contract" {
+}
+
+data "akamai_group" "group" {
+}
+
+resource "akamai_cp_code" "cp_code" {
+	name = "terraform-testing-renamed"
+	contract = "${data.akamai_contract.contract.id}"
+	group = "${data.akamai_group.group.id}"
+	product = "prd_SPM"
+}
+`
+}
+
 func testAccAkamaiCpCodeConfig() string {
 	return `
 provider "akamai" {
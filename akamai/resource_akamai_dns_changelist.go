@@ -0,0 +1,230 @@
+package akamai
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// resourceDNSv2Changelist submits the changelist that akamai_dns_record
+// resources with stage set write to. It runs after the records it depends on,
+// so the changelist is only submitted once every record was staged, and is
+// left unsubmitted when any of them fails.
+//
+// Its triggers are the zone_file attributes of the staged records, so the diff
+// is planned from the configuration, and records removed from the triggers are
+// deleted with the same changelist.
+func resourceDNSv2Changelist() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceDNSv2ChangelistCreate,
+		Read:          resourceDNSv2ChangelistRead,
+		Update:        resourceDNSv2ChangelistUpdate,
+		Delete:        resourceDNSv2ChangelistDelete,
+		CustomizeDiff: resourceDNSv2ChangelistCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"diff": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// triggerRecordsets parses the zone file records of the triggers.
+func triggerRecordsets(zone string, triggers map[string]interface{}) ([]dnsv2.Recordset, error) {
+	keys := make([]string, 0, len(triggers))
+	for k := range triggers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var recordsets []dnsv2.Recordset
+	for _, k := range keys {
+		parsed, err := parseZoneFile(zone, triggers[k].(string))
+		if err != nil {
+			return nil, fmt.Errorf("triggers value %q is not the zone_file of a staged record: %s", k, err)
+		}
+		recordsets = append(recordsets, parsed...)
+	}
+	return recordsets, nil
+}
+
+// removedRecordsets returns the recordsets of the old triggers that are not in
+// the new ones, without rdata.
+func removedRecordsets(old []dnsv2.Recordset, new []dnsv2.Recordset) []dnsv2.Recordset {
+	kept := make(map[string]bool, len(new))
+	for _, rs := range new {
+		kept[recordsetKey(rs.Name, rs.Type)] = true
+	}
+
+	var removed []dnsv2.Recordset
+	for _, rs := range old {
+		if !kept[recordsetKey(rs.Name, rs.Type)] {
+			kept[recordsetKey(rs.Name, rs.Type)] = true
+			removed = append(removed, dnsv2.Recordset{Name: rs.Name, Type: rs.Type})
+		}
+	}
+	return removed
+}
+
+// changelistTriggerChanges returns the recordsets the triggers stage and the
+// recordsets removed from them.
+func changelistTriggerChanges(zone string, d resourceChangeGetter) ([]dnsv2.Recordset, []dnsv2.Recordset, error) {
+	o, n := d.GetChange("triggers")
+	old, err := triggerRecordsets(zone, o.(map[string]interface{}))
+	if err != nil {
+		// Records cannot be deleted for previous triggers that are not zone
+		// file records.
+		log.Printf("[WARN] [Akamai DNSv2] Ignoring previous triggers of changelist of zone %s: %s", zone, err)
+		old = nil
+	}
+	staged, err := triggerRecordsets(zone, n.(map[string]interface{}))
+	if err != nil {
+		return nil, nil, err
+	}
+	return staged, removedRecordsets(old, staged), nil
+}
+
+// resourceDNSv2ChangelistCustomizeDiff plans the diff of the changelist from
+// the triggers and the live zone.
+func resourceDNSv2ChangelistCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("triggers") {
+		return nil
+	}
+	if !d.NewValueKnown("zone") || !d.NewValueKnown("triggers") {
+		log.Printf("[DEBUG] [Akamai DNSv2] Triggers are not known yet, planning changelist diff at apply")
+		return d.SetNewComputed("diff")
+	}
+
+	zone := d.Get("zone").(string)
+	staged, removed, err := changelistTriggerChanges(zone, d)
+	if err != nil {
+		return err
+	}
+	live, err := getZoneRecordsets(zone)
+	if err != nil {
+		if !isNotFoundError(err) {
+			return fmt.Errorf("unable to plan changelist of zone %s: %s", zone, err)
+		}
+		live = nil
+	}
+	return d.SetNew("diff", plannedChangelistDiff(live, append(staged, removed...)))
+}
+
+// plannedChangelistDiff returns the diff of staging recordsets, limited to the
+// recordsets they stage. Recordsets without rdata are removed.
+func plannedChangelistDiff(live []dnsv2.Recordset, recordsets []dnsv2.Recordset) []string {
+	affected := make(map[string]bool, len(recordsets))
+	for _, rs := range recordsets {
+		affected[recordsetKey(rs.Name, rs.Type)] = true
+	}
+
+	var before []dnsv2.Recordset
+	for _, rs := range live {
+		if affected[recordsetKey(rs.Name, rs.Type)] {
+			before = append(before, rs)
+		}
+	}
+	after := before
+	for _, rs := range recordsets {
+		after = replaceRecordset(after, rs)
+	}
+	return changelistDiff(before, after)
+}
+
+func resourceDNSv2ChangelistCreate(d *schema.ResourceData, meta interface{}) error {
+	zone := d.Get("zone").(string)
+
+	if err := submitChangelist(d); err != nil {
+		return err
+	}
+	d.SetId(zone)
+	return nil
+}
+
+func resourceDNSv2ChangelistUpdate(d *schema.ResourceData, meta interface{}) error {
+	return submitChangelist(d)
+}
+
+// submitChangelist stages the delete of the records removed from the triggers
+// and submits the changelist.
+func submitChangelist(d *schema.ResourceData) error {
+	zone := d.Get("zone").(string)
+
+	_, removed, err := changelistTriggerChanges(zone, d)
+	if err != nil {
+		return err
+	}
+	planned := d.Get("diff").([]interface{})
+
+	for _, rs := range removed {
+		log.Printf("[INFO] [Akamai DNSv2] Staging delete of %s %s removed from the triggers of changelist of zone %s", rs.Type, rs.Name, zone)
+		if err := stageRecordset(zone, rs); err != nil {
+			return fmt.Errorf("unable to stage delete of %s record %q in zone %s, the changelist of the zone was left unsubmitted: %s", rs.Type, rs.Name, zone, err)
+		}
+	}
+
+	diff, err := submitStagedChangelist(zone)
+	if err != nil {
+		return fmt.Errorf("unable to submit changelist of zone %s, it was left unsubmitted: %s", zone, err)
+	}
+	log.Printf("[INFO] [Akamai DNSv2] Submitted changelist of zone %s with %d record changes", zone, len(diff))
+	if len(planned) > 0 && fmt.Sprint(planned) != fmt.Sprint(diff) {
+		log.Printf("[WARN] [Akamai DNSv2] Changelist of zone %s differs from the plan, it contained changes staged outside of its triggers:\n%s", zone, strings.Join(diff, "\n"))
+	}
+
+	return d.Set("diff", diff)
+}
+
+// resourceDNSv2ChangelistRead keeps the diff of the last submission, which
+// cannot be read back once the changelist is submitted.
+func resourceDNSv2ChangelistRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+// resourceDNSv2ChangelistDelete deletes the records of the triggers with a
+// single changelist. It runs before the records it references are deleted,
+// which then find nothing left to delete.
+func resourceDNSv2ChangelistDelete(d *schema.ResourceData, meta interface{}) error {
+	zone := d.Get("zone").(string)
+
+	recordsets, err := triggerRecordsets(zone, d.Get("triggers").(map[string]interface{}))
+	if err != nil {
+		log.Printf("[WARN] [Akamai DNSv2] Not deleting the records of changelist of zone %s: %s", zone, err)
+		recordsets = nil
+	}
+
+	removed := removedRecordsets(recordsets, nil)
+	for _, rs := range removed {
+		if err := stageRecordset(zone, rs); err != nil {
+			return fmt.Errorf("unable to stage delete of %s record %q in zone %s, the changelist of the zone was left unsubmitted: %s", rs.Type, rs.Name, zone, err)
+		}
+	}
+	if len(removed) > 0 {
+		diff, err := submitStagedChangelist(zone)
+		if err != nil {
+			return fmt.Errorf("unable to submit changelist of zone %s, it was left unsubmitted: %s", zone, err)
+		}
+		log.Printf("[INFO] [Akamai DNSv2] Submitted changelist of zone %s with %d record deletes", zone, len(diff))
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,258 @@
+package akamai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+var testAccAkamaiDNSChangelistConfig = `
+provider "akamai" {
+  dns_section = "dns"
+}
+
+data "akamai_contract" "contract" {
+}
+
+data "akamai_group" "group" {
+}
+
+resource "akamai_dns_zone" "test_zone" {
+	contract = "${data.akamai_contract.contract.id}"
+	zone = "exampleterraform.io"
+	type = "primary"
+	comment =  "This is a test zone"
+	group     = "${data.akamai_group.group.id}"
+	sign_and_serve = false
+}
+
+resource "akamai_dns_record" "www" {
+	zone = "${akamai_dns_zone.test_zone.zone}"
+	name = "www.exampleterraform.io"
+	recordtype = "A"
+	active = true
+	stage = true
+	ttl = 300
+	target = ["10.0.0.2", "10.0.0.3"]
+}
+
+resource "akamai_dns_record" "api" {
+	zone = "${akamai_dns_zone.test_zone.zone}"
+	name = "api.exampleterraform.io"
+	recordtype = "CNAME"
+	active = true
+	stage = true
+	ttl = 300
+	target = ["www.exampleterraform.io."]
+}
+
+resource "akamai_dns_changelist" "test_zone" {
+	zone = "${akamai_dns_zone.test_zone.zone}"
+	triggers = {
+		www = "${akamai_dns_record.www.zone_file}"
+		api = "${akamai_dns_record.api.zone_file}"
+	}
+}
+`
+
+// testAccAkamaiDNSChangelistConfigRemoved drops the api record, whose delete
+// is staged and submitted by the changelist it was removed from.
+var testAccAkamaiDNSChangelistConfigRemoved = strings.Replace(
+	strings.Replace(testAccAkamaiDNSChangelistConfig, `
+resource "akamai_dns_record" "api" {
+	zone = "${akamai_dns_zone.test_zone.zone}"
+	name = "api.exampleterraform.io"
+	recordtype = "CNAME"
+	active = true
+	stage = true
+	ttl = 300
+	target = ["www.exampleterraform.io."]
+}
+`, "", 1),
+	`		api = "${akamai_dns_record.api.zone_file}"
+`, "", 1)
+
+func TestAccAkamaiDNSChangelist_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAkamaiDNSZoneDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAkamaiDNSChangelistConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("akamai_dns_changelist.test_zone", "diff.#", "3"),
+				),
+			},
+			{
+				Config: testAccAkamaiDNSChangelistConfigRemoved,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAkamaiDNSRecordRemoved("exampleterraform.io", "api.exampleterraform.io", "CNAME"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAkamaiDNSRecordRemoved(zone string, host string, recordtype string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rdata, err := dnsv2.GetRdata(zone, host, recordtype)
+		if err != nil && !isNotFoundError(err) {
+			return fmt.Errorf("error looking up "+recordtype+" records for %q: %s", host, err)
+		}
+		if len(rdata) > 0 {
+			return fmt.Errorf("%s record %q in zone %s still exists: %v", recordtype, host, zone, rdata)
+		}
+		if _, err := dnsv2.GetChangeList(zone); err == nil {
+			return fmt.Errorf("changelist of zone %s was left unsubmitted", zone)
+		}
+		return nil
+	}
+}
+
+func TestChangelistDiff(t *testing.T) {
+	live := []dnsv2.Recordset{
+		{Name: "www.example.com", Type: "A", TTL: 300, Rdata: []string{"10.0.0.1", "10.0.0.2"}},
+		{Name: "old.example.com", Type: "CNAME", TTL: 300, Rdata: []string{"www.example.com."}},
+	}
+	staged := []dnsv2.Recordset{
+		{Name: "www.example.com.", Type: "A", TTL: 300, Rdata: []string{"10.0.0.2", "10.0.0.3"}},
+		{Name: "new.example.com", Type: "CNAME", TTL: 300, Rdata: []string{"www.example.com"}},
+	}
+
+	expected := []string{
+		"+ new.example.com 300 CNAME www.example.com.",
+		"- old.example.com 300 CNAME www.example.com.",
+		"- www.example.com 300 A 10.0.0.1",
+		"+ www.example.com 300 A 10.0.0.3",
+	}
+	if diff := changelistDiff(live, staged); !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Value %v is invalid, expected %v", diff, expected)
+	}
+	if diff := changelistDiff(live, live); len(diff) != 0 {
+		t.Errorf("Value %v is invalid, expected no changes", diff)
+	}
+}
+
+func TestPlannedChangelistDiff(t *testing.T) {
+	www := recordZoneFile(dnsv2.RecordBody{Name: "www.example.com", RecordType: "A", TTL: 300, Target: []string{"10.0.0.3", "10.0.0.2"}})
+	if www != "www.example.com. 300 A 10.0.0.2\nwww.example.com. 300 A 10.0.0.3" {
+		t.Errorf("Value %v is invalid", www)
+	}
+
+	o := map[string]interface{}{"www": www, "api": "api.example.com. 300 CNAME www.example.com."}
+	n := map[string]interface{}{"www": strings.Replace(www, " 300 ", " 60 ", -1)}
+	old, err := triggerRecordsets("example.com", o)
+	if err != nil {
+		t.Fatalf("Value %v is invalid: %v", o, err)
+	}
+	staged, err := triggerRecordsets("example.com", n)
+	if err != nil {
+		t.Fatalf("Value %v is invalid: %v", n, err)
+	}
+	removed := removedRecordsets(old, staged)
+	if len(removed) != 1 || removed[0].Name != "api.example.com" || len(removed[0].Rdata) != 0 {
+		t.Errorf("Value %v is invalid", removed)
+	}
+
+	live := []dnsv2.Recordset{
+		{Name: "www.example.com", Type: "A", TTL: 300, Rdata: []string{"10.0.0.2", "10.0.0.3"}},
+		{Name: "api.example.com", Type: "CNAME", TTL: 300, Rdata: []string{"www.example.com."}},
+		{Name: "mail.example.com", Type: "A", TTL: 300, Rdata: []string{"10.0.0.4"}},
+	}
+	diff := plannedChangelistDiff(live, append(staged, removed...))
+	expected := []string{
+		"- api.example.com 300 CNAME www.example.com.",
+		"- www.example.com 300 A 10.0.0.2",
+		"- www.example.com 300 A 10.0.0.3",
+		"+ www.example.com 60 A 10.0.0.2",
+		"+ www.example.com 60 A 10.0.0.3",
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Value %v is invalid, expected %v", diff, expected)
+	}
+
+	if _, err := triggerRecordsets("example.com", map[string]interface{}{"www": "10.0.0.2,10.0.0.3"}); err == nil {
+		t.Errorf("Value %v should be invalid", "10.0.0.2,10.0.0.3")
+	}
+}
+
+func TestReplaceRecordset(t *testing.T) {
+	recordsets := []dnsv2.Recordset{
+		{Name: "www.example.com", Type: "A", TTL: 300, Rdata: []string{"10.0.0.1"}},
+		{Name: "www.example.com", Type: "AAAA", TTL: 300, Rdata: []string{"2001:db8::1"}},
+	}
+
+	replaced := replaceRecordset(recordsets, dnsv2.Recordset{Name: "WWW.example.com.", Type: "A", TTL: 60, Rdata: []string{"10.0.0.2"}})
+	if len(replaced) != 2 || replaced[1].TTL != 60 || replaced[0].Type != "AAAA" {
+		t.Errorf("Value %v is invalid", replaced)
+	}
+
+	removed := replaceRecordset(recordsets, dnsv2.Recordset{Name: "www.example.com", Type: "AAAA"})
+	if len(removed) != 1 || removed[0].Type != "A" {
+		t.Errorf("Value %v is invalid", removed)
+	}
+	if len(recordsets) != 2 {
+		t.Errorf("Value %v is invalid: input was modified", recordsets)
+	}
+}
+
+func TestSubmitRecordset(t *testing.T) {
+	var requests []string
+	var change map[string]interface{}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/config-dns/v2/zones/example.com/recordsets":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"recordsets":[{"name":"www.example.com","type":"A","ttl":300,"rdata":["10.0.0.1"]}]}`)
+		case r.Method == "GET":
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasSuffix(r.URL.Path, "/add-change"):
+			json.NewDecoder(r.Body).Decode(&change)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "POST":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+	httpClient, config := client.Client, dnsv2.Config
+	client.Client = srv.Client()
+	dnsv2.Config = edgegrid.Config{Host: srv.URL, ClientToken: "test", ClientSecret: "test", AccessToken: "test", MaxBody: 131072}
+	defer func() { client.Client, dnsv2.Config = httpClient, config }()
+
+	if err := submitRecordset("example.com", dnsv2.Recordset{Name: "www.example.com", Type: "A", TTL: 300, Rdata: []string{"10.0.0.2"}}); err != nil {
+		t.Fatalf("Value %v is invalid: %v", requests, err)
+	}
+	for _, req := range requests {
+		if strings.HasPrefix(req, "PUT ") {
+			t.Errorf("Value %v is invalid: the zone should not be replaced", requests)
+		}
+	}
+	if change["op"] != "EDIT" || change["name"] != "www.example.com" || !reflect.DeepEqual(change["rdata"], []interface{}{"10.0.0.2"}) {
+		t.Errorf("Value %v is invalid", change)
+	}
+	if last := requests[len(requests)-1]; last != "POST /config-dns/v2/changelists/example.com/submit" {
+		t.Errorf("Value %v is invalid: the changelist should be submitted", requests)
+	}
+
+	requests = nil
+	if err := submitRecordset("example.com", dnsv2.Recordset{Name: "www.example.com", Type: "A", TTL: 300, Rdata: []string{"10.0.0.1"}}); err != nil {
+		t.Fatalf("Value %v is invalid: %v", requests, err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("Value %v is invalid: an unchanged recordset should not create a changelist", requests)
+	}
+}
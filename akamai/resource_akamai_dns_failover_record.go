@@ -0,0 +1,465 @@
+package akamai
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/configgtm-v1_4"
+	reportsgtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/reportsgtm-v1"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// Edge DNS Failover Records
+//
+// A failover record serves its primary targets, or its secondary targets when
+// failover is set, e.g. from the akamai_gtm_property_liveness data source, or
+// when GTM finds the primary servers down with the liveness test of the
+// health_check block. Both are read once when the plan is made, so the planned
+// targets are exactly the ones applied. The served targets are stored in
+// target, so the record is built with bindRecord, and every swap is submitted
+// as a single changelist.
+
+const (
+	failoverPrimary   = "primary"
+	failoverSecondary = "secondary"
+
+	// failoverLivenessTestPrefix starts the name of the GTM liveness tests
+	// managed by failover records, which akamai_gtm_property leaves alone.
+	failoverLivenessTestPrefix = "failover-"
+)
+
+func resourceDNSv2FailoverRecord() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceDNSFailoverRecordCreate,
+		Read:          resourceDNSFailoverRecordRead,
+		Update:        resourceDNSFailoverRecordUpdate,
+		Delete:        resourceDNSFailoverRecordDelete,
+		CustomizeDiff: resourceDNSFailoverRecordCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"recordtype": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{RRTypeA, RRTypeAaaa, RRTypeCname}, false),
+			},
+			"ttl": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"primary": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+			"secondary": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+			"failover": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"health_check": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"domain": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.NoZeroValues,
+						},
+						"property": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.NoZeroValues,
+						},
+						"datacenter_id": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"protocol": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"HTTP", "HTTPS", "TCP"}, false),
+						},
+						"port": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntBetween(1, 65535),
+						},
+						"path": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "/",
+						},
+						"host_header": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"interval": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      60,
+							ValidateFunc: validation.IntAtLeast(gtmMinTestInterval),
+						},
+						"timeout": {
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							Default:      5,
+							ValidateFunc: validation.FloatBetween(gtmMinTestTimeout, gtmMaxTestTimeout),
+						},
+					},
+				},
+			},
+			"serving": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"target": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+		},
+	}
+}
+
+// failoverTargets returns which targets the record should serve, and the
+// targets themselves.
+func failoverTargets(d resourceGetter, failover bool) (string, []string) {
+	if failover {
+		return failoverSecondary, setToStringSlice(d.Get("secondary").(*schema.Set))
+	}
+	return failoverPrimary, setToStringSlice(d.Get("primary").(*schema.Set))
+}
+
+// recordFailover reports whether the record should serve its secondary
+// targets, because failover is set or because GTM finds every primary server
+// down. A new record serves its primary targets until its liveness test runs.
+func recordFailover(d resourceGetter, id string) (bool, error) {
+	if d.Get("failover").(bool) {
+		return true, nil
+	}
+	hc := expandFailoverHealthCheck(d)
+	if hc == nil || id == "" {
+		return false, nil
+	}
+
+	domain, property, dcID := hc["domain"].(string), hc["property"].(string), hc["datacenter_id"].(int)
+	stat, err := reportsgtm.GetIpStatusPerProperty(domain, property, map[string]string{
+		"mostRecent":   "true",
+		"datacenterId": strconv.Itoa(dcID),
+	})
+	if err != nil {
+		return false, fmt.Errorf("liveness report retrieval for property %s failed: %s", property, err)
+	}
+
+	alive, down := datacenterServers(stat, dcID)
+	if len(alive)+len(down) == 0 {
+		// A reporting gap never swaps the record.
+		log.Printf("[WARN] [Akamai DNSv2] No liveness data for property %s datacenter %d, keeping the served targets", property, dcID)
+		return d.Get("serving").(string) == failoverSecondary, nil
+	}
+	log.Printf("[DEBUG] [Akamai DNSv2] Property [%s] datacenter [%d] alive %v, down %v", property, dcID, alive, down)
+	return len(alive) == 0, nil
+}
+
+func expandFailoverHealthCheck(d resourceGetter) map[string]interface{} {
+	list, ok := d.Get("health_check").([]interface{})
+	if !ok || len(list) == 0 || list[0] == nil {
+		return nil
+	}
+	return list[0].(map[string]interface{})
+}
+
+// failoverLivenessTestName returns the name of the GTM liveness test of the
+// health check of a record.
+func failoverLivenessTestName(host string, recordtype string) string {
+	return failoverLivenessTestPrefix + strings.ToLower(strings.TrimSuffix(host, ".")) + "-" + strings.ToLower(recordtype)
+}
+
+func isFailoverLivenessTest(name string) bool {
+	return strings.HasPrefix(name, failoverLivenessTestPrefix)
+}
+
+// failoverLivenessTest returns the GTM liveness test that probes the servers
+// of the health check datacenter.
+func failoverLivenessTest(name string, hc map[string]interface{}) *gtm.LivenessTest {
+	protocol := hc["protocol"].(string)
+	lt := &gtm.LivenessTest{
+		Name:               name,
+		TestObjectProtocol: protocol,
+		TestObjectPort:     hc["port"].(int),
+		TestInterval:       hc["interval"].(int),
+		TestTimeout:        float32(hc["timeout"].(float64)),
+	}
+	if protocol != "TCP" {
+		lt.TestObject = hc["path"].(string)
+		lt.HttpError4xx = true
+		lt.HttpError5xx = true
+		if host := hc["host_header"].(string); host != "" {
+			lt.HttpHeaders = []*gtm.HttpHeader{{Name: "Host", Value: host}}
+		}
+	}
+	return lt
+}
+
+// setFailoverLivenessTest adds or replaces the liveness test called name on a
+// GTM property, or removes it when lt is nil.
+func setFailoverLivenessTest(domain string, property string, name string, lt *gtm.LivenessTest) error {
+	without := func(tests []*gtm.LivenessTest) []*gtm.LivenessTest {
+		result := make([]*gtm.LivenessTest, 0, len(tests)+1)
+		for _, t := range tests {
+			if t.Name != name {
+				result = append(result, t)
+			}
+		}
+		return result
+	}
+
+	uStat, err := updateGTMProperty(domain, property, func(prop *gtm.Property) error {
+		prop.LivenessTests = without(prop.LivenessTests)
+		if lt != nil {
+			prop.LivenessTests = append(prop.LivenessTests, lt)
+		}
+		return nil
+	}, func(prop *gtm.Property) bool {
+		return (len(without(prop.LivenessTests)) < len(prop.LivenessTests)) == (lt != nil)
+	})
+	if err != nil {
+		return err
+	}
+	if uStat.PropagationStatus == "DENIED" {
+		return errors.New(uStat.Message)
+	}
+	return nil
+}
+
+// applyFailoverHealthCheck moves the liveness test of the record to the
+// configured health check, and removes it from the previous one.
+func applyFailoverHealthCheck(d *schema.ResourceData) error {
+	name := failoverLivenessTestName(d.Get("name").(string), d.Get("recordtype").(string))
+	o, n := d.GetChange("health_check")
+
+	var oldHC, newHC map[string]interface{}
+	if l := o.([]interface{}); len(l) > 0 && l[0] != nil {
+		oldHC = l[0].(map[string]interface{})
+	}
+	if l := n.([]interface{}); len(l) > 0 && l[0] != nil {
+		newHC = l[0].(map[string]interface{})
+	}
+
+	if oldHC != nil && (newHC == nil || oldHC["domain"] != newHC["domain"] || oldHC["property"] != newHC["property"]) {
+		log.Printf("[INFO] [Akamai DNSv2] Removing liveness test %s from property %s", name, oldHC["property"])
+		err := setFailoverLivenessTest(oldHC["domain"].(string), oldHC["property"].(string), name, nil)
+		if cErr, ok := err.(gtm.CommonError); err != nil && !(ok && cErr.NotFound()) {
+			return err
+		}
+	}
+	if newHC != nil {
+		log.Printf("[INFO] [Akamai DNSv2] Setting liveness test %s on property %s", name, newHC["property"])
+		return setFailoverLivenessTest(newHC["domain"].(string), newHC["property"].(string), name, failoverLivenessTest(name, newHC))
+	}
+	return nil
+}
+
+// servingTargets reports which of primary and secondary the live rdata matches,
+// or an empty string when it matches neither.
+func servingTargets(d resourceGetter, rdata []string) string {
+	recordtype := d.Get("recordtype").(string)
+	live := strings.Join(normalizeRecordsetRdata(recordtype, rdata), "\n")
+
+	if live == strings.Join(canonicalTargets(recordtype, d.Get("primary").(*schema.Set)), "\n") {
+		return failoverPrimary
+	}
+	if live == strings.Join(canonicalTargets(recordtype, d.Get("secondary").(*schema.Set)), "\n") {
+		return failoverSecondary
+	}
+	return ""
+}
+
+func setToStringSlice(set *schema.Set) []string {
+	result := make([]string, 0, set.Len())
+	for _, v := range set.List() {
+		result = append(result, v.(string))
+	}
+	return result
+}
+
+// resourceDNSFailoverRecordCustomizeDiff validates the targets and the health
+// check, and plans the targets the record will serve.
+func resourceDNSFailoverRecordCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	for _, k := range []string{"recordtype", "primary", "secondary", "failover", "health_check"} {
+		if !d.NewValueKnown(k) {
+			log.Printf("[DEBUG] [Akamai DNSv2] Value of %s is not known yet, planning failover record targets at apply", k)
+			if err := d.SetNewComputed("serving"); err != nil {
+				return err
+			}
+			return d.SetNewComputed("target")
+		}
+	}
+
+	recordtype := d.Get("recordtype").(string)
+	for _, k := range []string{"primary", "secondary"} {
+		targets := setToStringSlice(d.Get(k).(*schema.Set))
+		for _, t := range targets {
+			if err := checkTargetValue(recordtype, t); err != nil {
+				return fmt.Errorf("DNS failover record validation failure on zone %v: %v", d.Get("zone"), err)
+			}
+		}
+		if recordtype == RRTypeCname && len(targets) > 1 {
+			return fmt.Errorf("DNS failover record validation failure on zone %v: Type CNAME must have a single %s target.", d.Get("zone"), k)
+		}
+	}
+
+	if hc := expandFailoverHealthCheck(d); hc != nil {
+		if hc["timeout"].(float64) >= float64(hc["interval"].(int)) {
+			return fmt.Errorf("DNS failover record validation failure on zone %v: health_check timeout %v must be less than interval %d", d.Get("zone"), hc["timeout"], hc["interval"])
+		}
+		if hc["protocol"].(string) == "TCP" && hc["host_header"].(string) != "" {
+			return fmt.Errorf("DNS failover record validation failure on zone %v: health_check host_header only applies to HTTP and HTTPS checks", d.Get("zone"))
+		}
+	}
+
+	failover, err := recordFailover(d, d.Id())
+	if err != nil {
+		return err
+	}
+	serving, targets := failoverTargets(d, failover)
+	current := setToStringSlice(d.Get("target").(*schema.Set))
+	if d.Id() != "" && d.Get("serving").(string) == serving &&
+		strings.Join(normalizeRecordsetRdata(recordtype, current), "\n") == strings.Join(normalizeRecordsetRdata(recordtype, targets), "\n") {
+		return nil
+	}
+
+	if err := d.SetNew("serving", serving); err != nil {
+		return err
+	}
+	return d.SetNew("target", targets)
+}
+
+func resourceDNSFailoverRecordCreate(d *schema.ResourceData, meta interface{}) error {
+	zone := d.Get("zone").(string)
+	host := d.Get("name").(string)
+	recordtype := d.Get("recordtype").(string)
+
+	if err := applyFailoverHealthCheck(d); err != nil {
+		return fmt.Errorf("unable to create health check of %s failover record %q in zone %s: %s", recordtype, host, zone, err)
+	}
+	if err := applyFailoverRecord(d); err != nil {
+		return fmt.Errorf("unable to create %s failover record %q in zone %s: %s", recordtype, host, zone, err)
+	}
+
+	d.SetId(dnsRecordID(zone, host, recordtype))
+	return resourceDNSFailoverRecordRead(d, meta)
+}
+
+func resourceDNSFailoverRecordUpdate(d *schema.ResourceData, meta interface{}) error {
+	zone := d.Get("zone").(string)
+	host := d.Get("name").(string)
+	recordtype := d.Get("recordtype").(string)
+
+	if d.HasChange("health_check") {
+		if err := applyFailoverHealthCheck(d); err != nil {
+			return fmt.Errorf("unable to update health check of %s failover record %q in zone %s: %s", recordtype, host, zone, err)
+		}
+	}
+	if err := applyFailoverRecord(d); err != nil {
+		return fmt.Errorf("unable to update %s failover record %q in zone %s: %s", recordtype, host, zone, err)
+	}
+
+	return resourceDNSFailoverRecordRead(d, meta)
+}
+
+// applyFailoverRecord submits the targets the record should serve, which are
+// the targets planned by resourceDNSFailoverRecordCustomizeDiff. Targets that
+// could not be planned are decided now.
+func applyFailoverRecord(d *schema.ResourceData) error {
+	zone := d.Get("zone").(string)
+
+	if d.Get("serving").(string) == "" {
+		failover, err := recordFailover(d, d.Id())
+		if err != nil {
+			return err
+		}
+		serving, targets := failoverTargets(d, failover)
+		d.Set("serving", serving)
+		if err := d.Set("target", targets); err != nil {
+			return err
+		}
+	}
+
+	record := bindRecord(d)
+	log.Printf("[INFO] [Akamai DNSv2] Serving %s targets %v for %s %s", d.Get("serving"), record.Target, record.RecordType, record.Name)
+	return submitRecordset(zone, dnsv2.Recordset{Name: record.Name, Type: record.RecordType, TTL: record.TTL, Rdata: record.Target})
+}
+
+func resourceDNSFailoverRecordRead(d *schema.ResourceData, meta interface{}) error {
+	zone := d.Get("zone").(string)
+	host := d.Get("name").(string)
+	recordtype := d.Get("recordtype").(string)
+
+	log.Printf("[INFO] [Akamai DNSv2] READ Searching for failover record %s %s %s", zone, host, recordtype)
+	recordset, err := getRecordSet(zone, host, recordtype)
+	if err != nil {
+		return fmt.Errorf("error looking up "+recordtype+" records for %q: %s", host, err)
+	}
+	if recordset == nil || len(recordset.Rdata) == 0 {
+		log.Printf("[WARN] [Akamai DNSv2] READ failover record not found [%s] [%s] [%s], removing from state", zone, host, recordtype)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("ttl", recordset.TTL)
+	d.Set("serving", servingTargets(d, recordset.Rdata))
+	return d.Set("target", recordset.Rdata)
+}
+
+func resourceDNSFailoverRecordDelete(d *schema.ResourceData, meta interface{}) error {
+	zone := d.Get("zone").(string)
+	host := d.Get("name").(string)
+	recordtype := d.Get("recordtype").(string)
+
+	err := submitRecordset(zone, dnsv2.Recordset{Name: host, Type: recordtype})
+	if err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("unable to delete %s failover record %q in zone %s: %s", recordtype, host, zone, err)
+	}
+
+	if hc := expandFailoverHealthCheck(d); hc != nil {
+		name := failoverLivenessTestName(host, recordtype)
+		err := setFailoverLivenessTest(hc["domain"].(string), hc["property"].(string), name, nil)
+		if cErr, ok := err.(gtm.CommonError); err != nil && !(ok && cErr.NotFound()) {
+			return fmt.Errorf("unable to delete health check of %s failover record %q in zone %s: %s", recordtype, host, zone, err)
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
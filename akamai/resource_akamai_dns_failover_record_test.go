@@ -0,0 +1,163 @@
+package akamai
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+var testAccAkamaiDNSFailoverRecordConfig = `
+provider "akamai" {
+  dns_section = "dns"
+}
+
+data "akamai_contract" "contract" {
+}
+
+data "akamai_group" "group" {
+}
+
+resource "akamai_dns_zone" "test_zone" {
+	contract = "${data.akamai_contract.contract.id}"
+	zone = "exampleterraform.io"
+	type = "primary"
+	comment =  "This is a test zone"
+	group     = "${data.akamai_group.group.id}"
+	sign_and_serve = false
+}
+
+resource "akamai_dns_failover_record" "www" {
+	zone = "${akamai_dns_zone.test_zone.zone}"
+	name = "www.exampleterraform.io"
+	recordtype = "A"
+	ttl = 60
+	primary = ["10.0.0.2", "10.0.0.3"]
+	secondary = ["10.1.0.2"]
+	failover = %t
+}
+`
+
+func TestAccAkamaiDNSFailoverRecord_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAkamaiDNSFailoverRecordDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccAkamaiDNSFailoverRecordConfig, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("akamai_dns_failover_record.www", "serving", "primary"),
+					resource.TestCheckResourceAttr("akamai_dns_failover_record.www", "target.#", "2"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccAkamaiDNSFailoverRecordConfig, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("akamai_dns_failover_record.www", "serving", "secondary"),
+					resource.TestCheckResourceAttr("akamai_dns_failover_record.www", "target.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAkamaiDNSFailoverRecordDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "akamai_dns_failover_record" {
+			continue
+		}
+
+		a := rs.Primary.Attributes
+		recordset, err := getRecordSet(a["zone"], a["name"], a["recordtype"])
+		if err != nil {
+			return err
+		}
+		if recordset != nil && len(recordset.Rdata) > 0 {
+			return fmt.Errorf("failover record %s still exists", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func TestFailoverTargets(t *testing.T) {
+	resourceSchema := resourceDNSv2FailoverRecord().Schema
+	config := map[string]interface{}{
+		"zone":       "exampleterraform.io",
+		"name":       "www.exampleterraform.io",
+		"recordtype": "AAAA",
+		"ttl":        60,
+		"primary":    []interface{}{"2001:db8::1"},
+		"secondary":  []interface{}{"2001:db8::2"},
+	}
+
+	serving, targets := failoverTargets(schema.TestResourceDataRaw(t, resourceSchema, config), false)
+	if serving != failoverPrimary || len(targets) != 1 || targets[0] != "2001:db8::1" {
+		t.Errorf("Value %v %v is invalid", serving, targets)
+	}
+
+	config["failover"] = true
+	d := schema.TestResourceDataRaw(t, resourceSchema, config)
+	failover, err := recordFailover(d, "")
+	if err != nil || !failover {
+		t.Errorf("Value %v should fail over", config)
+	}
+	serving, targets = failoverTargets(d, failover)
+	if serving != failoverSecondary || len(targets) != 1 || targets[0] != "2001:db8::2" {
+		t.Errorf("Value %v %v is invalid", serving, targets)
+	}
+
+	if s := servingTargets(d, []string{"2001:0db8:0000:0000:0000:0000:0000:0002"}); s != failoverSecondary {
+		t.Errorf("Value %v is invalid", s)
+	}
+	if s := servingTargets(d, []string{"2001:db8::3"}); s != "" {
+		t.Errorf("Value %v is invalid", s)
+	}
+}
+
+func TestFailoverLivenessTest(t *testing.T) {
+	hc := map[string]interface{}{
+		"domain":        "example.akadns.net",
+		"property":      "www",
+		"datacenter_id": 3131,
+		"protocol":      "HTTPS",
+		"port":          443,
+		"path":          "/health",
+		"host_header":   "www.example.com",
+		"interval":      60,
+		"timeout":       5.0,
+	}
+
+	name := failoverLivenessTestName("WWW.example.com.", "A")
+	if name != "failover-www.example.com-a" || !isFailoverLivenessTest(name) {
+		t.Errorf("Value %v is invalid", name)
+	}
+
+	lt := failoverLivenessTest(name, hc)
+	if lt.TestObjectProtocol != "HTTPS" || lt.TestObjectPort != 443 || lt.TestObject != "/health" || len(lt.HttpHeaders) != 1 || lt.HttpHeaders[0].Value != "www.example.com" {
+		t.Errorf("Value %#v is invalid", lt)
+	}
+
+	hc["protocol"] = "TCP"
+	hc["host_header"] = ""
+	lt = failoverLivenessTest(name, hc)
+	if lt.TestObject != "" || lt.HttpError4xx || len(lt.HttpHeaders) != 0 {
+		t.Errorf("Value %#v is invalid: TCP checks take no HTTP settings", lt)
+	}
+
+	// A new record serves its primary targets until its liveness test runs.
+	d := schema.TestResourceDataRaw(t, resourceDNSv2FailoverRecord().Schema, map[string]interface{}{
+		"zone":         "example.com",
+		"name":         "www.example.com",
+		"recordtype":   "A",
+		"ttl":          60,
+		"primary":      []interface{}{"192.0.2.10"},
+		"secondary":    []interface{}{"198.51.100.10"},
+		"health_check": []interface{}{hc},
+	})
+	if failover, err := recordFailover(d, ""); err != nil || failover {
+		t.Errorf("Value %v %v is invalid", failover, err)
+	}
+}
@@ -25,6 +25,24 @@ func resourceDNSv2Record() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceDNSRecordImport,
 		},
+		CustomizeDiff: resourceDNSRecordCustomizeDiff,
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceDNSv2RecordV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceDNSv2RecordStateUpgradeV0,
+				Version: 0,
+			},
+		},
+		Schema: akamaiDNSv2RecordSchema,
+	}
+}
+
+// resourceDNSv2RecordV0 is the record schema used with zone-name-type-sha1 IDs.
+// It is a frozen copy, so later changes to akamaiDNSv2RecordSchema do not change
+// how version 0 states are decoded.
+func resourceDNSv2RecordV0() *schema.Resource {
+	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
 			"zone": {
 				Type:         schema.TypeString,
@@ -223,6 +241,312 @@ func resourceDNSv2Record() *schema.Resource {
 	}
 }
 
+// resourceDNSv2RecordStateUpgradeV0 replaces the zone-name-type-sha1 ID, which
+// changed with every target update, with the zone/name/type ID.
+func resourceDNSv2RecordStateUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	zone, _ := rawState["zone"].(string)
+	host, _ := rawState["name"].(string)
+	recordtype, _ := rawState["recordtype"].(string)
+	if zone == "" || host == "" || recordtype == "" {
+		return nil, fmt.Errorf("unable to upgrade DNS record state with ID %v: zone, name and recordtype must be set", rawState["id"])
+	}
+
+	rawState["id"] = dnsRecordID(zone, host, recordtype)
+	log.Printf("[DEBUG] [Akamai DNSv2] Upgraded record ID to %s", rawState["id"])
+	return rawState, nil
+}
+
+var akamaiDNSv2RecordSchema = map[string]*schema.Schema{
+	"zone": {
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.NoZeroValues,
+	},
+	"name": {
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+	},
+	"recordtype": {
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+		ValidateFunc: validation.StringInSlice([]string{
+			RRTypeA,
+			RRTypeAaaa,
+			RRTypeCname,
+			RRTypeLoc,
+			RRTypeNs,
+			RRTypePtr,
+			RRTypeSpf,
+			RRTypeTxt,
+			RRTypeAfsdb,
+			RRTypeDnskey,
+			RRTypeDs,
+			RRTypeHinfo,
+			RRTypeMx,
+			RRTypeNaptr,
+			RRTypeNsec3,
+			RRTypeNsec3Param,
+			RRTypeRp,
+			RRTypeRrsig,
+			RRTypeSrv,
+			RRTypeSshfp,
+			RRTypeAkamaiCdn,
+			RRTypeAkamaiTlc,
+			RRTypeCaa,
+			RRTypeCert,
+			RRTypeHttps,
+			RRTypeSoa,
+			RRTypeSvcb,
+			RRTypeTlsa,
+		}, false),
+	},
+	"ttl": {
+		Type:     schema.TypeInt,
+		Required: true,
+	},
+	"active": {
+		Type:     schema.TypeBool,
+		Required: true,
+	},
+	"stage": {
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	},
+	"target": {
+		Type:             schema.TypeSet,
+		Elem:             &schema.Schema{Type: schema.TypeString},
+		Optional:         true,
+		Set:              schema.HashString,
+		DiffSuppressFunc: suppressEquivalentTargets,
+	},
+	"subtype": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"flags": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"protocol": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"algorithm": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"key": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"keytag": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"digest_type": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"digest": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"hardware": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"software": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"priority": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"order": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"preference": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"flagsnaptr": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"service": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"regexp": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"replacement": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"iterations": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"salt": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"next_hashed_owner_name": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"type_bitmaps": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"mailbox": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"txt": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"type_covered": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"original_ttl": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"expiration": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"inception": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"signer": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"signature": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"labels": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"weight": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"port": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"fingerprint_type": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"fingerprint": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"priority_increment": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"tag": {
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.StringInSlice([]string{"issue", "issuewild", "iodef"}, false),
+	},
+	"usage": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"selector": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"match_type": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"certificate": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"type_mnemonic": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"type_value": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"name_server": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"email_address": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"serial": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"refresh": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"retry": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"expiry": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"nxdomain_ttl": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"svc_priority": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"target_name": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"svc_params": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"answer_type": {
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.StringInSlice([]string{"DUALSTACK", "IPV4", "IPV6"}, false),
+	},
+	"dns_name": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"zone_file": {
+		Type:     schema.TypeString,
+		Computed: true,
+	},
+}
+
 // Create a new DNS Record
 func resourceDNSRecordCreate(d *schema.ResourceData, meta interface{}) error {
 	// only allow one record to be created at a time
@@ -245,61 +569,51 @@ func resourceDNSRecordCreate(d *schema.ResourceData, meta interface{}) error {
 		recordtype = d.Get("recordtype").(string)
 	}
 
-	err := validateRecord(d)
-	if err != nil {
-		return fmt.Errorf("DNS record validation failure on zone %v: %v", zone, err)
-	}
-
 	recordcreate := bindRecord(d)
 	extractString := strings.Join(recordcreate.Target, " ")
 	sha1hash := getSHAString(extractString)
 
 	log.Printf("[DEBUG] [Akamai DNSv2] SHA sum for recordcreate [%s]", sha1hash)
-	// First try to get the zone from the API
-	log.Printf("[DEBUG] [Akamai DNSv2] Searching for records [%s]", zone)
+	d.Set("zone_file", recordZoneFile(recordcreate))
 
-	rdata, e := dnsv2.GetRdata(zone, host, recordtype)
-	if e != nil {
-		return fmt.Errorf("error looking up "+recordtype+" records for %q: %s", host, e)
+	if d.Get("stage").(bool) {
+		if e := stageRecord(zone, recordcreate); e != nil {
+			return e
+		}
+		d.SetId(dnsRecordID(zone, host, recordtype))
+		return resourceDNSRecordRead(d, meta)
 	}
 
-	log.Printf("[DEBUG] [Akamai DNSv2] Searching for records LEN %d", len(rdata))
-	if len(rdata) > 0 {
-		extractString := strings.Join(rdata, " ")
-		sha1hashtest := getSHAString(extractString)
-
-		log.Printf("[DEBUG] [Akamai DNSv2] SHA sum from recordread [%s]", sha1hashtest)
-	}
-	// If there's no existing record we'll create a blank one
-	if dnsv2.IsConfigDNSError(e) && e.(dnsv2.ConfigDNSError).NotFound() == true {
-		// if the record is not found/404 we will create a new
-		log.Printf("[DEBUG] [Akamai DNSv2] [ERROR] %s", e.Error())
-		log.Printf("[DEBUG] [Akamai DNSv2] Creating new record")
-		// Save the zone to the API
-		e = recordcreate.Save(zone)
+	// First try to get the zone from the API
+	log.Printf("[DEBUG] [Akamai DNSv2] Searching for records [%s]", zone)
 
+	// Look up and write the record under the zone lock, so parallel writes
+	// to the zone cannot race between the two
+	e := withZoneLock(zone, func() error {
+		rdata, e := dnsv2.GetRdata(zone, host, recordtype)
 		if e != nil {
-			return e
+			return fmt.Errorf("error looking up "+recordtype+" records for %q: %s", host, e)
 		}
-	} else {
-		log.Printf("[DEBUG] [Akamai DNSv2] Updating record")
+
+		log.Printf("[DEBUG] [Akamai DNSv2] Searching for records LEN %d", len(rdata))
 		if len(rdata) > 0 {
-			e = recordcreate.Update(zone)
-			if e != nil {
-				return e
-			}
-		} else {
-			log.Printf("[DEBUG] [Akamai DNSv2] Saving record")
-			e = recordcreate.Save(zone)
-			if e != nil {
-				return e
-			}
+			extractString := strings.Join(rdata, " ")
+			sha1hashtest := getSHAString(extractString)
+
+			log.Printf("[DEBUG] [Akamai DNSv2] SHA sum from recordread [%s]", sha1hashtest)
+			log.Printf("[DEBUG] [Akamai DNSv2] Updating record")
+			return writeRecord("PUT", zone, recordcreate)
 		}
 
+		log.Printf("[DEBUG] [Akamai DNSv2] Saving record")
+		return writeRecord("POST", zone, recordcreate)
+	})
+	if e != nil {
+		return e
 	}
 
 	// Give terraform the ID
-	d.SetId(fmt.Sprintf("%s-%s-%s-%s", zone, host, recordtype, sha1hash))
+	d.SetId(dnsRecordID(zone, host, recordtype))
 
 	return resourceDNSRecordUpdate(d, meta)
 }
@@ -335,49 +649,45 @@ func resourceDNSRecordUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
-	err := validateRecord(d)
-	if err != nil {
-		return fmt.Errorf("DNS record validation failure on zone %v: %v", zone, err)
-	}
-
 	recordcreate := bindRecord(d)
 	extractString := strings.Join(recordcreate.Target, " ")
 	sha1hash := getSHAString(extractString)
 
 	log.Printf("[DEBUG] [Akamai DNSv2] UPDATE SHA sum for recordupdate [%s]", sha1hash)
+	d.Set("zone_file", recordZoneFile(recordcreate))
+
+	if d.Get("stage").(bool) {
+		if e := stageRecord(zone, recordcreate); e != nil {
+			return e
+		}
+		return resourceDNSRecordRead(d, meta)
+	}
+
 	// First try to get the zone from the API
 	log.Printf("[DEBUG] [Akamai DNSv2] UPDATE Searching for records [%s]", zone)
 
-	rdata, e := dnsv2.GetRdata(zone, host, recordtype)
-	if e != nil {
-		return fmt.Errorf("error looking up "+recordtype+" records for %q: %s", host, e)
-	}
+	e := withZoneLock(zone, func() error {
+		rdata, e := dnsv2.GetRdata(zone, host, recordtype)
+		if e != nil {
+			return fmt.Errorf("error looking up "+recordtype+" records for %q: %s", host, e)
+		}
+
+		log.Printf("[DEBUG] [Akamai DNSv2] UPDATE Searching for records LEN %d", len(rdata))
+		if len(rdata) == 0 {
+			return nil
+		}
 
-	log.Printf("[DEBUG] [Akamai DNSv2] UPDATE Searching for records LEN %d", len(rdata))
-	if len(rdata) > 0 {
 		sort.Strings(rdata)
 		extractString := strings.Join(recordcreate.Target, " ")
 		sha1hashtest := getSHAString(extractString)
 		log.Printf("[DEBUG] [Akamai DNSv2] UPDATE SHA sum from recordread [%s]", sha1hashtest)
-		// If there's no existing record we'll create a blank one
-		if dnsv2.IsConfigDNSError(e) && e.(dnsv2.ConfigDNSError).NotFound() == true {
-			// if the record is not found/404 we will create a new
-			log.Printf("[DEBUG] [Akamai DNSv2] UPDATE [ERROR] %s", e.Error())
-			log.Printf("[DEBUG] [Akamai DNSv2] UPDATE Creating new record")
-			// Save the zone to the API
-			log.Printf("[DEBUG] [Akamai DNSv2] UPDATE Updating record")
-			e = recordcreate.Save(zone)
-
-		} else {
-			log.Printf("[DEBUG] [Akamai DNSv2] UPDATE Updating record")
-			e = recordcreate.Update(zone)
-			if e != nil {
-				return e
-			}
-
-		}
-		d.SetId(fmt.Sprintf("%s-%s-%s-%s", zone, host, recordtype, sha1hash))
+		log.Printf("[DEBUG] [Akamai DNSv2] UPDATE Updating record")
+		return writeRecord("PUT", zone, recordcreate)
+	})
+	if e != nil {
+		return e
 	}
+	d.SetId(dnsRecordID(zone, host, recordtype))
 
 	// Give terraform the ID
 
@@ -410,20 +720,24 @@ func resourceDNSRecordRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	log.Printf("[DEBUG] [Akamai DNSv2] READ record JSON from bind records %s %s %s %s", string(b), zone, host, recordtype)
-	//sort.Strings(recordcreate.Target)
-	extractString := strings.Join(recordcreate.Target, " ")
+	recordcreate.Target = normalizeRecordsetRdata(recordtype, recordcreate.Target)
+	extractString := strings.Join(comparableRdata(recordtype, recordcreate.Target), " ")
 	sha1hash := getSHAString(extractString)
 	log.Printf("[DEBUG] [Akamai DNSv2] READ SHA sum for Existing SHA test %s %s", extractString, sha1hash)
 
 	// try to get the zone from the API
 	log.Printf("[INFO] [Akamai DNSv2] READ Searching for zone records %s %s %s", zone, host, recordtype)
-	targets, e := dnsv2.GetRdata(zone, host, recordtype)
+	recordset, e := readRecordSet(d, zone, host, recordtype)
 	if e != nil {
-		//return fmt.Errorf("error looking up "+recordtype+" records for %q: %s", host, e,e)
+		return fmt.Errorf("error looking up "+recordtype+" records for %q: %s", host, e)
+	}
+	if recordset == nil || len(recordset.Rdata) == 0 {
+		log.Printf("[WARN] [Akamai DNSv2] READ record not found [%s] [%s] [%s], removing from state", zone, host, recordtype)
 		d.SetId("")
 		return nil
-
 	}
+
+	targets := recordset.Rdata
 	b1, err := json.Marshal(targets)
 	if err != nil {
 		fmt.Println(err)
@@ -431,33 +745,128 @@ func resourceDNSRecordRead(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[DEBUG] [Akamai DNSv2] READ record data read JSON %s", string(b1))
 
-	if len(targets) > 0 {
-		sort.Strings(targets)
-		extractStringTest := strings.Join(targets, " ")
-		sha1hashtest := getSHAString(extractStringTest)
+	d.Set("ttl", recordset.TTL)
 
-		if sha1hashtest == sha1hash {
-			log.Printf("[DEBUG] [Akamai DNSv2] READ SHA sum from recordExists matches [%s] vs  [%s] [%s] [%s] [%s] ", sha1hashtest, sha1hash, zone, host, recordtype)
-			d.SetId(fmt.Sprintf("%s-%s-%s-%s", zone, host, recordtype, sha1hash))
-			return nil
-		} else {
-			log.Printf("[DEBUG] [Akamai DNSv2] READ SHA sum from recordExists mismatch [%s] vs  [%s] [%s] [%s] [%s] ", sha1hashtest, sha1hash, zone, host, recordtype)
-			d.SetId("")
-			return nil
-		}
-	} else {
-		log.Printf("[DEBUG] [Akamai DNSv2] READ SHA sum from recordExists mismatch no target returned  [%s] [%s] [%s] ", zone, host, recordtype)
-		d.SetId("")
+	extractStringTest := strings.Join(comparableRdata(recordtype, targets), " ")
+	sha1hashtest := getSHAString(extractStringTest)
+	if sha1hashtest == sha1hash {
+		log.Printf("[DEBUG] [Akamai DNSv2] READ SHA sum from recordExists matches [%s] vs  [%s] [%s] [%s] [%s] ", sha1hashtest, sha1hash, zone, host, recordtype)
 		return nil
 	}
+
+	// The record was changed outside of Terraform, store what is actually
+	// served so the plan shows the difference instead of recreating the record.
+	log.Printf("[DEBUG] [Akamai DNSv2] READ SHA sum from recordExists mismatch [%s] vs  [%s] [%s] [%s] [%s] ", sha1hashtest, sha1hash, zone, host, recordtype)
+	fields, err := rdataFields(recordtype, targets)
+	if err != nil {
+		return fmt.Errorf("unable to read %s record %q in zone %s: %s", recordtype, host, zone, err)
+	}
+	for k, v := range fields {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getRecordSet returns the recordset for name and type with its rdata in the
+// same normalized form bindRecord produces. It returns nil when the record or
+// its zone does not exist.
+func getRecordSet(zone string, host string, recordtype string) (*dnsv2.Recordset, error) {
+	records, err := dnsv2.GetRecordList(zone, host, recordtype)
+	if err != nil {
+		if dnsv2.IsConfigDNSError(err) && err.(dnsv2.ConfigDNSError).NotFound() {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	for _, r := range records.Recordsets {
+		if r.Name != host || r.Type != recordtype {
+			continue
+		}
+
+		r.Rdata = normalizeRecordsetRdata(recordtype, r.Rdata)
+		return &r, nil
+	}
+
+	return nil, nil
+}
+
+// readRecordSet returns the recordset of a record, from the zone's changelist
+// when the record is staged.
+func readRecordSet(d *schema.ResourceData, zone string, host string, recordtype string) (*dnsv2.Recordset, error) {
+	if d.Get("stage").(bool) {
+		return getStagedRecordSet(zone, host, recordtype)
+	}
+	return getRecordSet(zone, host, recordtype)
+}
+
+// recordZoneFile returns a record in zone file format, one "name ttl type
+// rdata" line per target with absolute names, as planned from the
+// configuration. akamai_dns_changelist plans its diff from these lines.
+func recordZoneFile(record dnsv2.RecordBody) string {
+	rs := normalizeRecordset(dnsv2.Recordset{Name: record.Name, Type: record.RecordType, TTL: record.TTL, Rdata: record.Target})
+	rs.Name = fqdn(rs.Name)
+	return strings.Join(zoneFileRecords([]dnsv2.Recordset{rs}), "\n")
+}
+
+// stageRecord stages a record in the zone's changelist. A record without
+// targets is staged for deletion.
+func stageRecord(zone string, record dnsv2.RecordBody) error {
+	rs := dnsv2.Recordset{Name: record.Name, Type: record.RecordType, TTL: record.TTL, Rdata: record.Target}
+	if err := stageRecordset(zone, rs); err != nil {
+		return fmt.Errorf("unable to stage %s record %q in zone %s, the changelist of the zone was left unsubmitted: %s", record.RecordType, record.Name, zone, err)
+	}
 	return nil
 }
 
+// dnsRecordID returns the zone/name/type ID of a record.
+func dnsRecordID(zone string, host string, recordtype string) string {
+	return fmt.Sprintf("%s/%s/%s", zone, host, recordtype)
+}
+
+func parseDNSRecordID(id string) (zone string, host string, recordtype string, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid DNS record ID %q, expected zone/name/type", id)
+	}
+	return parts[0], parts[1], strings.ToUpper(parts[2]), nil
+}
+
 func resourceDNSRecordImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	hostname := d.Id()
+	zone, host, recordtype, err := parseDNSRecordID(d.Id())
+	if err != nil {
+		return nil, err
+	}
 
-	// find the zone first
-	log.Printf("[INFO] [Akamai DNS] Searching for zone Records [%s]", hostname)
+	// find the record first
+	log.Printf("[INFO] [Akamai DNSv2] IMPORT Searching for zone records %s %s %s", zone, host, recordtype)
+	recordset, err := getRecordSet(zone, host, recordtype)
+	if err != nil {
+		return nil, fmt.Errorf("error looking up "+recordtype+" records for %q: %s", host, err)
+	}
+	if recordset == nil || len(recordset.Rdata) == 0 {
+		return nil, fmt.Errorf("%s record %q not found in zone %s", recordtype, host, zone)
+	}
+
+	fields, err := rdataFields(recordtype, recordset.Rdata)
+	if err != nil {
+		return nil, fmt.Errorf("unable to import %s record %q in zone %s: %s", recordtype, host, zone, err)
+	}
+	for k, v := range fields {
+		if err := d.Set(k, v); err != nil {
+			return nil, err
+		}
+	}
+
+	d.Set("zone", zone)
+	d.Set("name", host)
+	d.Set("recordtype", recordtype)
+	d.Set("ttl", recordset.TTL)
+	d.Set("active", true)
+	d.SetId(dnsRecordID(zone, host, recordtype))
 
 	return []*schema.ResourceData{d}, nil
 }
@@ -469,6 +878,13 @@ func resourceDNSRecordDelete(d *schema.ResourceData, meta interface{}) error {
 	recordtype := d.Get("recordtype").(string)
 	ttl := d.Get("ttl").(int)
 
+	// Every zone has exactly one SOA record, it can only be updated.
+	if recordtype == RRTypeSoa {
+		log.Printf("[WARN] [Akamai DNS] SOA record for zone %s can't be deleted, removing from state only", zone)
+		d.SetId("")
+		return nil
+	}
+
 	target := d.Get("target").(*schema.Set).List()
 
 	records := make([]string, 0, len(target))
@@ -479,15 +895,40 @@ func resourceDNSRecordDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[INFO] [Akamai DNS] Delete zone Records %v", records)
 	recordcreate := dnsv2.RecordBody{Name: host, RecordType: recordtype, TTL: ttl, Target: records}
 
-	recordcreate.Delete(zone)
+	// akamai_dns_changelist stages and submits the delete of the records
+	// removed from its triggers, which may run before the record is deleted.
+	if d.Get("stage").(bool) {
+		recordset, e := getStagedRecordSet(zone, host, recordtype)
+		if e != nil {
+			return fmt.Errorf("error looking up "+recordtype+" records for %q: %s", host, e)
+		}
+		if recordset != nil && len(recordset.Rdata) > 0 {
+			recordcreate.Target = nil
+			if e := stageRecord(zone, recordcreate); e != nil {
+				return e
+			}
+		}
+		d.SetId("")
+		return nil
+	}
+
+	e := withZoneLock(zone, func() error {
+		return writeRecord("DELETE", zone, recordcreate)
+	})
+	if e != nil && !isNotFoundError(e) {
+		return fmt.Errorf("unable to delete %s record %q in zone %s: %s", recordtype, host, zone, e)
+	}
 
 	targets, e := dnsv2.GetRdata(zone, host, recordtype)
+	if e != nil && !isNotFoundError(e) {
+		return e
+	}
 	if len(targets) > 0 {
-		log.Printf("[INFO] [Akamai DNS] Delete zone Records record still exists %v %s", targets, e)
-		return nil
-	} else {
-		d.SetId("")
+		log.Printf("[INFO] [Akamai DNS] Delete zone Records record still exists %v", targets)
+		return fmt.Errorf("%s record %q in zone %s still exists after delete", recordtype, host, zone)
 	}
+
+	d.SetId("")
 	return nil
 }
 
@@ -510,49 +951,19 @@ func resourceDNSRecordExists(d *schema.ResourceData, meta interface{}) (bool, er
 		recordtype = d.Get("recordtype").(string)
 	}
 
-	recordcreate := bindRecord(d)
-
-	b, err := json.Marshal(recordcreate.Target)
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	log.Printf("[DEBUG] [Akamai DNSv2] EXISTS record JSON from bind records %s %s %s %s", string(b), zone, host, recordtype)
-	//sort.Strings(recordcreate.Target)
-	extractString := strings.Join(recordcreate.Target, " ")
-	sha1hash := getSHAString(extractString)
-	log.Printf("[DEBUG] [Akamai DNSv2] EXISTS SHA sum for Existing SHA test %s %s", extractString, sha1hash)
-
 	// try to get the zone from the API
 	log.Printf("[INFO] [Akamai DNSv2] EXISTS Searching for zone records %s %s %s", zone, host, recordtype)
-	targets, e := dnsv2.GetRdata(zone, host, recordtype)
+	recordset, e := readRecordSet(d, zone, host, recordtype)
 	if e != nil {
-		//return fmt.Errorf("error looking up "+recordtype+" records for %q: %s", host, e,e)
-		return false, nil
+		return false, fmt.Errorf("error looking up "+recordtype+" records for %q: %s", host, e)
 	}
-	b1, err := json.Marshal(targets)
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	log.Printf("[DEBUG] [Akamai DNSv2] EXISTS record data read JSON %s", string(b1))
-
-	if len(targets) > 0 {
-		sort.Strings(targets)
-		extractStringTest := strings.Join(targets, " ")
-		sha1hashtest := getSHAString(extractStringTest)
 
-		if sha1hashtest == sha1hash {
-			log.Printf("[DEBUG] [Akamai DNSv2] EXISTS SHA sum from recordExists matches [%s] vs  [%s] [%s] [%s] [%s] ", sha1hashtest, sha1hash, zone, host, recordtype)
-			return true, nil
-		} else {
-			log.Printf("[DEBUG] [Akamai DNSv2] EXISTS SHA sum from recordExists mismatch [%s] vs  [%s] [%s] [%s] [%s] ", sha1hashtest, sha1hash, zone, host, recordtype)
-			return false, nil
-		}
-	} else {
-		log.Printf("[DEBUG] [Akamai DNSv2] EXISTS SHA sum from recordExists msimatch no target retunred  [%s] [%s] [%s] ", zone, host, recordtype)
+	if recordset == nil || len(recordset.Rdata) == 0 {
+		log.Printf("[DEBUG] [Akamai DNSv2] EXISTS no target returned [%s] [%s] [%s] ", zone, host, recordtype)
 		return false, nil
 	}
+
+	return true, nil
 }
 
 func contains(s []string, e string) bool {
@@ -594,13 +1005,13 @@ func padvalue(str string) string {
 // Used to pad coordinates to x.xxm format
 func padCoordinates(str string) string {
 
-	s := strings.Split(str, " ")
+	s := strings.Fields(str)
 	latD, latM, latS, latDir, longD, longM, longS, longDir, altitude, size, horizPrecision, vertPrecision := s[0], s[1], s[2], s[3], s[4], s[5], s[6], s[7], s[8], s[9], s[10], s[11]
 	return latD + " " + latM + " " + latS + " " + latDir + " " + longD + " " + longM + " " + longS + " " + longDir + " " + padvalue(altitude) + "m " + padvalue(size) + "m " + padvalue(horizPrecision) + "m " + padvalue(vertPrecision) + "m"
 
 }
 
-func bindRecord(d *schema.ResourceData) dnsv2.RecordBody {
+func bindRecord(d resourceGetter) dnsv2.RecordBody {
 
 	var host string
 	var recordtype string
@@ -618,7 +1029,7 @@ func bindRecord(d *schema.ResourceData) dnsv2.RecordBody {
 	target := d.Get("target").(*schema.Set).List()
 	records := make([]string, 0, len(target))
 
-	simplerecordtarget := map[string]bool{"AAAA": true, "CNAME": true, "LOC": true, "NS": true, "PTR": true, "SPF": true, "SRV": true}
+	simplerecordtarget := map[string]bool{"AAAA": true, "CNAME": true, "LOC": true, "NS": true, "PTR": true, "SRV": true}
 
 	for _, recContent := range target {
 		if simplerecordtarget[recordtype] {
@@ -649,7 +1060,7 @@ func bindRecord(d *schema.ResourceData) dnsv2.RecordBody {
 
 	simplerecord := map[string]bool{"A": true, "AAAA": true, "AKAMAICDN": true, "CNAME": true, "LOC": true, "NS": true, "PTR": true, "SPF": true, "TXT": true}
 	if simplerecord[recordtype] {
-		sort.Strings(records)
+		records = normalizeRecordsetRdata(recordtype, records)
 
 		recordcreate := dnsv2.RecordBody{Name: host, RecordType: recordtype, TTL: ttl, Target: records}
 		return recordcreate
@@ -659,6 +1070,10 @@ func bindRecord(d *schema.ResourceData) dnsv2.RecordBody {
 			records := make([]string, 0, len(target))
 			subtype := d.Get("subtype").(int)
 			for _, recContent := range target {
+				if targetHasRdata(recordtype, recContent.(string)) {
+					records = append(records, normalizeRdata(recordtype, recContent.(string)))
+					continue
+				}
 				checktarget := recContent.(string)[len(recContent.(string))-1:]
 				if checktarget == "." {
 					records = append(records, strconv.Itoa(subtype)+" "+recContent.(string))
@@ -720,20 +1135,18 @@ func bindRecord(d *schema.ResourceData) dnsv2.RecordBody {
 		}
 		if recordtype == "MX" {
 
-			zone := d.Get("zone").(string)
-
-			rdata, e := dnsv2.GetRdata(zone, host, recordtype)
-			if e != nil {
-				log.Printf("[DEBUG] [Akamai DNSv2] Searching for existing MX records no prexisting targets found LEN %d", len(rdata))
-			}
-			log.Printf("[DEBUG] [Akamai DNSv2] Searching for existing MX records to append to target LEN %d", len(rdata))
-
-			records := make([]string, 0, len(target)+len(rdata))
+			// The rdata is built from the configuration only, so Read can
+			// compare it with the live record to detect drift.
+			records := make([]string, 0, len(target))
 			priority := d.Get("priority").(int)
 
 			increment := d.Get("priority_increment").(int)
 
 			for _, recContent := range target {
+				if targetHasRdata(recordtype, recContent.(string)) {
+					records = append(records, normalizeRdata(recordtype, recContent.(string)))
+					continue
+				}
 				checktarget := recContent.(string)[len(recContent.(string))-1:]
 				if checktarget != "." {
 					records = append(records, strconv.Itoa(priority)+" "+recContent.(string)+".")
@@ -747,20 +1160,6 @@ func bindRecord(d *schema.ResourceData) dnsv2.RecordBody {
 			}
 			log.Printf("[DEBUG] [Akamai DNSv2] Appended new target to target array LEN %d %v", len(records), records)
 
-			if len(rdata) > 0 {
-				log.Printf("[DEBUG] [Akamai DNSv2] rdata Exists MX records to append to target LEN %d", len(rdata))
-				for _, r := range rdata {
-					checktarget := r[len(r)-1:]
-					if checktarget != "." {
-						r = r + "."
-					}
-					if !(contains(records, r)) {
-						records = append(records, r)
-					}
-				}
-				log.Printf("[DEBUG] [Akamai DNSv2] Existing MX records to append to target before schema data LEN %d %v", len(rdata), records)
-
-			}
 			sort.Strings(records)
 			recordcreate := dnsv2.RecordBody{Name: host, RecordType: recordtype, TTL: ttl, Target: records}
 			return recordcreate
@@ -859,6 +1258,10 @@ func bindRecord(d *schema.ResourceData) dnsv2.RecordBody {
 			port := d.Get("port").(int)
 
 			for _, recContent := range target {
+				if targetHasRdata(recordtype, recContent.(string)) {
+					records = append(records, normalizeRdata(recordtype, recContent.(string)))
+					continue
+				}
 				checktarget := recContent.(string)[len(recContent.(string))-1:]
 				if checktarget == "." {
 					records = append(records, strconv.Itoa(priority)+" "+strconv.Itoa(weight)+" "+strconv.Itoa(port)+" "+recContent.(string))
@@ -880,6 +1283,106 @@ func bindRecord(d *schema.ResourceData) dnsv2.RecordBody {
 
 			records = append(records, strconv.Itoa(algorithm)+" "+strconv.Itoa(fingerprintType)+" "+fingerprint)
 
+			recordcreate := dnsv2.RecordBody{Name: host, RecordType: recordtype, TTL: ttl, Target: records}
+			return recordcreate
+		}
+		if recordtype == "CAA" {
+
+			records := make([]string, 0, len(target))
+			flags := d.Get("flags").(int)
+			tag := d.Get("tag").(string)
+
+			for _, recContent := range target {
+				if targetHasRdata(recordtype, recContent.(string)) {
+					records = append(records, normalizeRdata(recordtype, recContent.(string)))
+					continue
+				}
+				records = append(records, normalizeRdata(recordtype, strconv.Itoa(flags)+" "+tag+" "+recContent.(string)))
+			}
+			sort.Strings(records)
+			recordcreate := dnsv2.RecordBody{Name: host, RecordType: recordtype, TTL: ttl, Target: records}
+			return recordcreate
+		}
+		if recordtype == "TLSA" {
+
+			records := make([]string, 0, len(target))
+			usage := d.Get("usage").(int)
+			selector := d.Get("selector").(int)
+			matchType := d.Get("match_type").(int)
+			certificate := d.Get("certificate").(string)
+
+			records = append(records, normalizeRdata(recordtype, strconv.Itoa(usage)+" "+strconv.Itoa(selector)+" "+strconv.Itoa(matchType)+" "+certificate))
+
+			recordcreate := dnsv2.RecordBody{Name: host, RecordType: recordtype, TTL: ttl, Target: records}
+			return recordcreate
+		}
+		if recordtype == "CERT" {
+
+			records := make([]string, 0, len(target))
+			certType := d.Get("type_mnemonic").(string)
+			if certType == "" {
+				certType = strconv.Itoa(d.Get("type_value").(int))
+			}
+			keytag := d.Get("keytag").(int)
+			algorithm := d.Get("algorithm").(int)
+			certificate := d.Get("certificate").(string)
+
+			records = append(records, normalizeRdata(recordtype, certType+" "+strconv.Itoa(keytag)+" "+strconv.Itoa(algorithm)+" "+certificate))
+
+			recordcreate := dnsv2.RecordBody{Name: host, RecordType: recordtype, TTL: ttl, Target: records}
+			return recordcreate
+		}
+		if recordtype == "SOA" {
+
+			records := make([]string, 0, len(target))
+			nameServer := d.Get("name_server").(string)
+			emailAddress := d.Get("email_address").(string)
+			serial := d.Get("serial").(int)
+			refresh := d.Get("refresh").(int)
+			retry := d.Get("retry").(int)
+			expiry := d.Get("expiry").(int)
+			nxdomainTTL := d.Get("nxdomain_ttl").(int)
+
+			// The serial is maintained by Edge DNS, keep the current one
+			// unless it is set explicitly.
+			if serial == 0 {
+				zone := d.Get("zone").(string)
+				rdata, e := dnsv2.GetRdata(zone, host, recordtype)
+				if e != nil {
+					log.Printf("[DEBUG] [Akamai DNSv2] Searching for existing SOA record failed %s", e)
+				}
+				if len(rdata) > 0 {
+					if fields := strings.Fields(rdata[0]); len(fields) == 7 {
+						serial, _ = strconv.Atoi(fields[2])
+					}
+				}
+			}
+
+			records = append(records, normalizeRdata(recordtype, nameServer+" "+emailAddress+" "+strconv.Itoa(serial)+" "+strconv.Itoa(refresh)+" "+strconv.Itoa(retry)+" "+strconv.Itoa(expiry)+" "+strconv.Itoa(nxdomainTTL)))
+
+			recordcreate := dnsv2.RecordBody{Name: host, RecordType: recordtype, TTL: ttl, Target: records}
+			return recordcreate
+		}
+		if recordtype == "SVCB" || recordtype == "HTTPS" {
+
+			records := make([]string, 0, len(target))
+			svcPriority := d.Get("svc_priority").(int)
+			targetName := d.Get("target_name").(string)
+			svcParams := d.Get("svc_params").(string)
+
+			records = append(records, normalizeRdata(recordtype, strconv.Itoa(svcPriority)+" "+targetName+" "+svcParams))
+
+			recordcreate := dnsv2.RecordBody{Name: host, RecordType: recordtype, TTL: ttl, Target: records}
+			return recordcreate
+		}
+		if recordtype == "AKAMAITLC" {
+
+			records := make([]string, 0, len(target))
+			answerType := d.Get("answer_type").(string)
+			dnsName := d.Get("dns_name").(string)
+
+			records = append(records, normalizeRdata(recordtype, answerType+" "+dnsName))
+
 			recordcreate := dnsv2.RecordBody{Name: host, RecordType: recordtype, TTL: ttl, Target: records}
 			return recordcreate
 		}
@@ -887,7 +1390,545 @@ func bindRecord(d *schema.ResourceData) dnsv2.RecordBody {
 	return emptyrecordcreate
 }
 
-func validateRecord(d *schema.ResourceData) error {
+// fqdn returns name with the trailing dot the Edge DNS API uses for domain names.
+func fqdn(name string) string {
+	if name == "" || strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// normalizeRdata returns the canonical form of a single rdata string, so the
+// rdata built from the configuration and the rdata served by the API compare
+// equal when they describe the same record.
+func normalizeRdata(recordtype string, rdata string) string {
+	fields := strings.Fields(rdata)
+
+	switch recordtype {
+	case RRTypeCname, RRTypeNs, RRTypePtr:
+		if len(fields) != 1 {
+			return rdata
+		}
+		return fqdn(fields[0])
+	case RRTypeAfsdb, RRTypeMx, RRTypeSrv:
+		if len(fields) < 2 {
+			return rdata
+		}
+		fields[len(fields)-1] = fqdn(fields[len(fields)-1])
+		return strings.Join(fields, " ")
+	case RRTypeCaa:
+		parts := strings.SplitN(strings.Join(fields, " "), " ", 3)
+		if len(parts) != 3 {
+			return rdata
+		}
+		return parts[0] + " " + strings.ToLower(parts[1]) + " \"" + strings.Trim(parts[2], "\"") + "\""
+	case RRTypeTlsa:
+		if len(fields) < 4 {
+			return rdata
+		}
+		return strings.Join(fields[:3], " ") + " " + strings.ToUpper(strings.Join(fields[3:], ""))
+	case RRTypeCert:
+		if len(fields) < 4 {
+			return rdata
+		}
+		return strings.ToUpper(fields[0]) + " " + fields[1] + " " + fields[2] + " " + strings.Join(fields[3:], "")
+	case RRTypeSoa:
+		if len(fields) != 7 {
+			return rdata
+		}
+		return fqdn(fields[0]) + " " + fqdn(fields[1]) + " " + strings.Join(fields[2:], " ")
+	case RRTypeSvcb, RRTypeHttps:
+		if len(fields) < 2 {
+			return rdata
+		}
+		return strings.Join(append([]string{fields[0], fqdn(fields[1])}, fields[2:]...), " ")
+	case RRTypeAkamaiTlc:
+		return strings.Join(fields, " ")
+	case RRTypeTxt, RRTypeSpf:
+		return txtRdata(rdata)
+	}
+
+	return rdata
+}
+
+// txtRdataChunkSize is the longest character string a TXT record can hold.
+const txtRdataChunkSize = 255
+
+// txtRdata returns TXT rdata as quoted character strings of at most 255 bytes,
+// the form Edge DNS serves. An unquoted value is a single character string.
+func txtRdata(rdata string) string {
+	content := txtContent(rdata)
+
+	var chunks []string
+	var chunk strings.Builder
+	size := 0
+	for i := 0; i < len(content); {
+		// An escape sequence is a single byte of the character string
+		n := 1
+		if content[i] == '\\' && i+1 < len(content) {
+			n = 2
+			if i+3 < len(content) && isDigits(content[i+1:i+4]) {
+				n = 4
+			}
+		}
+		if size == txtRdataChunkSize {
+			chunks = append(chunks, chunk.String())
+			chunk.Reset()
+			size = 0
+		}
+		chunk.WriteString(content[i : i+n])
+		size++
+		i += n
+	}
+	chunks = append(chunks, chunk.String())
+
+	return "\"" + strings.Join(chunks, "\" \"") + "\""
+}
+
+// txtContent joins the quoted character strings of TXT rdata. Unquoted rdata is
+// returned as is, with any double quote escaped.
+func txtContent(rdata string) string {
+	rdata = strings.TrimSpace(rdata)
+	var content strings.Builder
+
+	if !strings.HasPrefix(rdata, "\"") {
+		for i := 0; i < len(rdata); i++ {
+			if rdata[i] == '\\' && i+1 < len(rdata) {
+				content.WriteString(rdata[i : i+2])
+				i++
+				continue
+			}
+			if rdata[i] == '"' {
+				content.WriteByte('\\')
+			}
+			content.WriteByte(rdata[i])
+		}
+		return content.String()
+	}
+
+	inQuote := false
+	for i := 0; i < len(rdata); i++ {
+		c := rdata[i]
+		switch {
+		case c == '\\' && i+1 < len(rdata):
+			content.WriteString(rdata[i : i+2])
+			i++
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+			content.WriteByte(c)
+		case c != ' ' && c != '\t':
+			// Malformed quoting, keep the rdata as it is
+			return strings.Trim(rdata, "\"")
+		}
+	}
+	return content.String()
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// suppressEquivalentTargets suppresses target changes that only differ in form,
+// such as compressed IPv6 addresses, missing trailing dots or TXT quoting.
+func suppressEquivalentTargets(k, old, new string, d *schema.ResourceData) bool {
+	recordtype := d.Get("recordtype").(string)
+	o, n := d.GetChange("target")
+	oldTargets, ok := o.(*schema.Set)
+	if !ok {
+		return false
+	}
+	newTargets, ok := n.(*schema.Set)
+	if !ok {
+		return false
+	}
+
+	return strings.Join(canonicalTargets(recordtype, oldTargets), "\n") == strings.Join(canonicalTargets(recordtype, newTargets), "\n")
+}
+
+// canonicalTargets returns the sorted canonical form of the targets of a record.
+func canonicalTargets(recordtype string, targets *schema.Set) []string {
+	rdata := make([]string, 0, targets.Len())
+	for _, t := range targets.List() {
+		rdata = append(rdata, t.(string))
+	}
+	return normalizeRecordsetRdata(recordtype, rdata)
+}
+
+// normalizeRecordsetRdata returns the sorted, normalized rdata of a recordset
+// in the form bindRecord produces.
+func normalizeRecordsetRdata(recordtype string, rdata []string) []string {
+	result := make([]string, 0, len(rdata))
+	for _, str := range rdata {
+		if recordtype == RRTypeAaaa {
+			if addr := net.ParseIP(str); addr != nil {
+				str = FullIPv6(addr)
+			}
+		} else if recordtype == RRTypeLoc && len(strings.Fields(str)) == 12 {
+			str = padCoordinates(str)
+		}
+		result = append(result, normalizeRdata(recordtype, str))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// comparableRdata returns the rdata used to detect changes to a record. The
+// SOA serial is maintained by Edge DNS and is left out.
+func comparableRdata(recordtype string, rdata []string) []string {
+	if recordtype != RRTypeSoa {
+		return rdata
+	}
+
+	result := make([]string, 0, len(rdata))
+	for _, r := range rdata {
+		fields := strings.Fields(r)
+		if len(fields) == 7 {
+			fields[2] = "0"
+		}
+		result = append(result, strings.Join(fields, " "))
+	}
+	return result
+}
+
+// targetHasRdata reports whether a target of an MX, SRV, AFSDB or CAA record
+// is complete rdata carrying its own priority, weight and port, subtype or
+// flags and tag, such as "10 mx1.example.com.", instead of taking them from
+// the record.
+func targetHasRdata(recordtype string, target string) bool {
+	fields := strings.Fields(target)
+	switch recordtype {
+	case RRTypeMx, RRTypeAfsdb:
+		return len(fields) == 2 && isDigits(fields[0])
+	case RRTypeSrv:
+		return len(fields) == 4 && isDigits(fields[0]) && isDigits(fields[1]) && isDigits(fields[2])
+	case RRTypeCaa:
+		return len(fields) >= 3 && isDigits(fields[0])
+	}
+	return false
+}
+
+// rdataTargets returns rdata as targets that carry their own values, for
+// records whose values differ between targets.
+func rdataTargets(rdata []string) []interface{} {
+	targets := make([]interface{}, 0, len(rdata))
+	for _, r := range rdata {
+		targets = append(targets, strings.Join(strings.Fields(r), " "))
+	}
+	return targets
+}
+
+// rdataFields is the inverse of bindRecord: it splits the rdata served for a
+// record back into the resource attributes bindRecord builds it from. Values
+// that differ between the targets of a record are kept in each target.
+func rdataFields(recordtype string, rdata []string) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if len(rdata) == 0 {
+		return fields, nil
+	}
+
+	// split returns the n whitespace separated fields of an rdata string, the
+	// last one holding the remainder.
+	split := func(r string, n int) ([]string, error) {
+		parts := strings.SplitN(strings.Join(strings.Fields(r), " "), " ", n)
+		if len(parts) != n {
+			return nil, fmt.Errorf("unexpected %s rdata %q", recordtype, r)
+		}
+		return parts, nil
+	}
+	ints := func(parts ...string) ([]int, error) {
+		values := make([]int, 0, len(parts))
+		for _, p := range parts {
+			v, err := strconv.Atoi(p)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected %s rdata value %q", recordtype, p)
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	}
+
+	switch recordtype {
+	case RRTypeA, RRTypeAaaa, RRTypeAkamaiCdn, RRTypeCname, RRTypeLoc, RRTypeNs, RRTypePtr, RRTypeSpf, RRTypeTxt:
+		targets := make([]interface{}, 0, len(rdata))
+		for _, r := range rdata {
+			targets = append(targets, r)
+		}
+		fields["target"] = targets
+	case RRTypeAfsdb:
+		targets := make([]interface{}, 0, len(rdata))
+		subtypes := make(map[int]bool)
+		for _, r := range rdata {
+			parts, err := split(r, 2)
+			if err != nil {
+				return nil, err
+			}
+			v, err := ints(parts[0])
+			if err != nil {
+				return nil, err
+			}
+			fields["subtype"] = v[0]
+			subtypes[v[0]] = true
+			targets = append(targets, parts[1])
+		}
+		fields["target"] = targets
+		if len(subtypes) > 1 {
+			fields["subtype"], fields["target"] = 0, rdataTargets(rdata)
+		}
+	case RRTypeDnskey:
+		parts, err := split(rdata[0], 4)
+		if err != nil {
+			return nil, err
+		}
+		v, err := ints(parts[:3]...)
+		if err != nil {
+			return nil, err
+		}
+		fields["flags"], fields["protocol"], fields["algorithm"] = v[0], v[1], v[2]
+		fields["key"] = parts[3]
+	case RRTypeDs:
+		parts, err := split(rdata[0], 4)
+		if err != nil {
+			return nil, err
+		}
+		v, err := ints(parts[:3]...)
+		if err != nil {
+			return nil, err
+		}
+		fields["keytag"], fields["digest_type"], fields["algorithm"] = v[0], v[1], v[2]
+		fields["digest"] = parts[3]
+	case RRTypeHinfo:
+		parts, err := split(rdata[0], 2)
+		if err != nil {
+			return nil, err
+		}
+		fields["hardware"], fields["software"] = parts[0], parts[1]
+	case RRTypeMx:
+		targets := make([]interface{}, 0, len(rdata))
+		priorities := make(map[int]bool)
+		for _, r := range rdata {
+			parts, err := split(r, 2)
+			if err != nil {
+				return nil, err
+			}
+			v, err := ints(parts[0])
+			if err != nil {
+				return nil, err
+			}
+			fields["priority"] = v[0]
+			priorities[v[0]] = true
+			targets = append(targets, parts[1])
+		}
+		fields["priority_increment"] = 0
+		fields["target"] = targets
+		if len(priorities) > 1 {
+			fields["priority"], fields["target"] = 0, rdataTargets(rdata)
+		}
+	case RRTypeNaptr:
+		parts, err := split(rdata[0], 6)
+		if err != nil {
+			return nil, err
+		}
+		v, err := ints(parts[:2]...)
+		if err != nil {
+			return nil, err
+		}
+		fields["order"], fields["preference"] = v[0], v[1]
+		fields["flagsnaptr"], fields["regexp"], fields["replacement"], fields["service"] = parts[2], parts[3], parts[4], parts[5]
+	case RRTypeNsec3:
+		parts, err := split(rdata[0], 6)
+		if err != nil {
+			return nil, err
+		}
+		v, err := ints(parts[:3]...)
+		if err != nil {
+			return nil, err
+		}
+		fields["flags"], fields["algorithm"], fields["iterations"] = v[0], v[1], v[2]
+		fields["salt"], fields["next_hashed_owner_name"], fields["type_bitmaps"] = parts[3], parts[4], parts[5]
+	case RRTypeNsec3Param:
+		parts, err := split(rdata[0], 4)
+		if err != nil {
+			return nil, err
+		}
+		v, err := ints(parts[:3]...)
+		if err != nil {
+			return nil, err
+		}
+		fields["flags"], fields["algorithm"], fields["iterations"] = v[0], v[1], v[2]
+		fields["salt"] = parts[3]
+	case RRTypeRp:
+		parts, err := split(rdata[0], 2)
+		if err != nil {
+			return nil, err
+		}
+		fields["mailbox"], fields["txt"] = parts[0], parts[1]
+	case RRTypeRrsig:
+		parts, err := split(rdata[0], 9)
+		if err != nil {
+			return nil, err
+		}
+		v, err := ints(parts[1], parts[2], parts[3], parts[8])
+		if err != nil {
+			return nil, err
+		}
+		fields["type_covered"] = parts[0]
+		fields["algorithm"], fields["labels"], fields["original_ttl"], fields["keytag"] = v[0], v[1], v[2], v[3]
+		fields["expiration"], fields["inception"], fields["signature"], fields["signer"] = parts[4], parts[5], parts[6], parts[7]
+	case RRTypeSrv:
+		targets := make([]interface{}, 0, len(rdata))
+		values := make(map[string]bool)
+		for _, r := range rdata {
+			parts, err := split(r, 4)
+			if err != nil {
+				return nil, err
+			}
+			v, err := ints(parts[:3]...)
+			if err != nil {
+				return nil, err
+			}
+			fields["priority"], fields["weight"], fields["port"] = v[0], v[1], v[2]
+			values[strings.Join(parts[:3], " ")] = true
+			targets = append(targets, parts[3])
+		}
+		fields["target"] = targets
+		if len(values) > 1 {
+			fields["priority"], fields["weight"], fields["port"] = 0, 0, 0
+			fields["target"] = rdataTargets(rdata)
+		}
+	case RRTypeSshfp:
+		parts, err := split(rdata[0], 3)
+		if err != nil {
+			return nil, err
+		}
+		v, err := ints(parts[:2]...)
+		if err != nil {
+			return nil, err
+		}
+		fields["algorithm"], fields["fingerprint_type"] = v[0], v[1]
+		fields["fingerprint"] = parts[2]
+	case RRTypeCaa:
+		targets := make([]interface{}, 0, len(rdata))
+		values := make(map[string]bool)
+		for _, r := range rdata {
+			parts, err := split(r, 3)
+			if err != nil {
+				return nil, err
+			}
+			v, err := ints(parts[0])
+			if err != nil {
+				return nil, err
+			}
+			fields["flags"], fields["tag"] = v[0], parts[1]
+			values[parts[0]+" "+parts[1]] = true
+			targets = append(targets, strings.Trim(parts[2], "\""))
+		}
+		fields["target"] = targets
+		if len(values) > 1 {
+			fields["flags"], fields["tag"], fields["target"] = 0, "", rdataTargets(rdata)
+		}
+	case RRTypeTlsa:
+		parts, err := split(rdata[0], 4)
+		if err != nil {
+			return nil, err
+		}
+		v, err := ints(parts[:3]...)
+		if err != nil {
+			return nil, err
+		}
+		fields["usage"], fields["selector"], fields["match_type"] = v[0], v[1], v[2]
+		fields["certificate"] = parts[3]
+	case RRTypeCert:
+		parts, err := split(rdata[0], 4)
+		if err != nil {
+			return nil, err
+		}
+		v, err := ints(parts[1:3]...)
+		if err != nil {
+			return nil, err
+		}
+		if certType, err := strconv.Atoi(parts[0]); err == nil {
+			fields["type_value"], fields["type_mnemonic"] = certType, ""
+		} else {
+			fields["type_value"], fields["type_mnemonic"] = 0, parts[0]
+		}
+		fields["keytag"], fields["algorithm"] = v[0], v[1]
+		fields["certificate"] = parts[3]
+	case RRTypeSoa:
+		parts, err := split(rdata[0], 7)
+		if err != nil {
+			return nil, err
+		}
+		v, err := ints(parts[3:]...)
+		if err != nil {
+			return nil, err
+		}
+		fields["name_server"], fields["email_address"] = parts[0], parts[1]
+		fields["refresh"], fields["retry"], fields["expiry"], fields["nxdomain_ttl"] = v[0], v[1], v[2], v[3]
+	case RRTypeSvcb, RRTypeHttps:
+		parts := strings.Fields(rdata[0])
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("unexpected %s rdata %q", recordtype, rdata[0])
+		}
+		v, err := ints(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		fields["svc_priority"], fields["target_name"] = v[0], parts[1]
+		fields["svc_params"] = strings.Join(parts[2:], " ")
+	case RRTypeAkamaiTlc:
+		parts, err := split(rdata[0], 2)
+		if err != nil {
+			return nil, err
+		}
+		fields["answer_type"], fields["dns_name"] = parts[0], parts[1]
+	default:
+		return nil, fmt.Errorf("Invalid recordtype %v", recordtype)
+	}
+
+	return fields, nil
+}
+
+// resourceGetter is the part of schema.ResourceData and schema.ResourceDiff
+// used to validate a record, so records can be validated at plan time.
+type resourceGetter interface {
+	Get(string) interface{}
+	GetOk(string) (interface{}, bool)
+}
+
+type resourceChangeGetter interface {
+	GetChange(string) (interface{}, interface{})
+}
+
+// resourceDNSRecordCustomizeDiff validates the record at plan time. Records
+// with values that are not known yet are validated once they are.
+func resourceDNSRecordCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	for k, s := range akamaiDNSv2RecordSchema {
+		if s.Computed {
+			continue
+		}
+		if !d.NewValueKnown(k) {
+			log.Printf("[DEBUG] [Akamai DNSv2] Value of %s is not known yet, skipping record validation", k)
+			return d.SetNewComputed("zone_file")
+		}
+	}
+
+	if err := validateRecord(d); err != nil {
+		return fmt.Errorf("DNS record validation failure on zone %v: %v", d.Get("zone"), err)
+	}
+
+	if zoneFile := recordZoneFile(bindRecord(d)); zoneFile != d.Get("zone_file").(string) {
+		return d.SetNew("zone_file", zoneFile)
+	}
+	return nil
+}
+
+func validateRecord(d resourceGetter) error {
 	var recordtype string
 	if v, ok := d.GetOk("recordtype"); ok {
 		recordtype = v.(string)
@@ -923,12 +1964,24 @@ func validateRecord(d *schema.ResourceData) error {
 		return checkSrvRecord(d)
 	case RRTypeSshfp:
 		return checkSshfpRecord(d)
+	case RRTypeCaa:
+		return checkCaaRecord(d)
+	case RRTypeTlsa:
+		return checkTlsaRecord(d)
+	case RRTypeCert:
+		return checkCertRecord(d)
+	case RRTypeSoa:
+		return checkSoaRecord(d)
+	case RRTypeSvcb, RRTypeHttps:
+		return checkSvcbRecord(d)
+	case RRTypeAkamaiTlc:
+		return checkAkamaiTlcRecord(d)
 	default:
 		return fmt.Errorf("Invalid recordtype %v", recordtype)
 	}
 }
 
-func checkBasicRecordTypes(d *schema.ResourceData) error {
+func checkBasicRecordTypes(d resourceGetter) error {
 	host := d.Get("name").(string)
 	recordtype := d.Get("recordtype").(string)
 	ttl := d.Get("ttl").(int)
@@ -948,7 +2001,7 @@ func checkBasicRecordTypes(d *schema.ResourceData) error {
 	return nil
 }
 
-func checkTargets(d *schema.ResourceData) error {
+func checkTargets(d resourceGetter) error {
 	target := d.Get("target").(*schema.Set).List()
 	records := make([]string, 0, len(target))
 
@@ -960,10 +2013,43 @@ func checkTargets(d *schema.ResourceData) error {
 		return fmt.Errorf("Type records must be set.")
 	}
 
+	recordtype := d.Get("recordtype").(string)
+	for _, record := range records {
+		if err := checkTargetValue(recordtype, record); err != nil {
+			return err
+		}
+	}
+	if recordtype == RRTypeCname && len(records) > 1 {
+		return fmt.Errorf("Type CNAME must have a single target.")
+	}
+
+	return nil
+}
+
+// checkTargetValue checks the format of a single target of a basic record.
+func checkTargetValue(recordtype string, target string) error {
+	switch recordtype {
+	case RRTypeA:
+		if ip := net.ParseIP(target); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("Target %q must be an IPv4 address for A.", target)
+		}
+	case RRTypeAaaa:
+		if ip := net.ParseIP(target); ip == nil || ip.To4() != nil {
+			return fmt.Errorf("Target %q must be an IPv6 address for AAAA.", target)
+		}
+	case RRTypeLoc:
+		if len(strings.Fields(target)) != 12 {
+			return fmt.Errorf("Target %q must have 12 fields for LOC.", target)
+		}
+	case RRTypeCname, RRTypeNs, RRTypePtr:
+		if target == "" || strings.ContainsAny(target, " \t") {
+			return fmt.Errorf("Target %q must be a domain name for %s.", target, recordtype)
+		}
+	}
 	return nil
 }
 
-func checkSimpleRecord(d *schema.ResourceData) error {
+func checkSimpleRecord(d resourceGetter) error {
 	if err := checkBasicRecordTypes(d); err != nil {
 		return err
 	}
@@ -975,9 +2061,22 @@ func checkSimpleRecord(d *schema.ResourceData) error {
 	return nil
 }
 
-func checkAsdfRecord(d *schema.ResourceData) error {
+// targetsHaveRdata reports whether every target of a record carries its own
+// values, in which case the record level values are not used.
+func targetsHaveRdata(d resourceGetter) bool {
+	recordtype := d.Get("recordtype").(string)
+	target := d.Get("target").(*schema.Set).List()
+	for _, recContent := range target {
+		if !targetHasRdata(recordtype, recContent.(string)) {
+			return false
+		}
+	}
+	return len(target) > 0
+}
+
+func checkAsdfRecord(d resourceGetter) error {
 	subtype := d.Get("subtype").(int)
-	if subtype == 0 {
+	if subtype == 0 && !targetsHaveRdata(d) {
 		return fmt.Errorf("Type subtype must be set for ASDF.")
 	}
 
@@ -988,10 +2087,10 @@ func checkAsdfRecord(d *schema.ResourceData) error {
 	return nil
 }
 
-func checkDnskeyRecord(d *schema.ResourceData) error {
+func checkDnskeyRecord(d resourceGetter) error {
 	flags := d.Get("flags").(int)
 	protocol := d.Get("protocol").(int)
-	algorithm := d.Get("algoritm").(int)
+	algorithm := d.Get("algorithm").(int)
 	key := d.Get("key").(string)
 	ttl := d.Get("ttl").(int)
 
@@ -1018,7 +2117,7 @@ func checkDnskeyRecord(d *schema.ResourceData) error {
 	return nil
 }
 
-func checkDsRecord(d *schema.ResourceData) error {
+func checkDsRecord(d resourceGetter) error {
 	digestType := d.Get("digest_type").(int)
 	keytag := d.Get("keytag").(int)
 	algorithm := d.Get("algorithm").(int)
@@ -1043,7 +2142,7 @@ func checkDsRecord(d *schema.ResourceData) error {
 	return nil
 }
 
-func checkHinfoRecord(d *schema.ResourceData) error {
+func checkHinfoRecord(d resourceGetter) error {
 	hardware := d.Get("hardware").(string)
 	software := d.Get("software").(string)
 
@@ -1058,7 +2157,7 @@ func checkHinfoRecord(d *schema.ResourceData) error {
 	return nil
 }
 
-func checkMxRecord(d *schema.ResourceData) error {
+func checkMxRecord(d resourceGetter) error {
 	priority := d.Get("priority").(int)
 
 	if err := checkBasicRecordTypes(d); err != nil {
@@ -1076,7 +2175,7 @@ func checkMxRecord(d *schema.ResourceData) error {
 	return nil
 }
 
-func checkNaptrRecord(d *schema.ResourceData) error {
+func checkNaptrRecord(d resourceGetter) error {
 	flagsnaptr := d.Get("flagsnaptr").(string)
 	order := d.Get("order").(int)
 	preference := d.Get("preference").(int)
@@ -1115,7 +2214,7 @@ func checkNaptrRecord(d *schema.ResourceData) error {
 	return nil
 }
 
-func checkNsec3Record(d *schema.ResourceData) error {
+func checkNsec3Record(d resourceGetter) error {
 	flags := d.Get("flags").(int)
 	algorithm := d.Get("algorithm").(int)
 	iterations := d.Get("iterations").(int)
@@ -1149,7 +2248,7 @@ func checkNsec3Record(d *schema.ResourceData) error {
 	return nil
 }
 
-func checkNsec3ParamRecord(d *schema.ResourceData) error {
+func checkNsec3ParamRecord(d resourceGetter) error {
 	flags := d.Get("flags").(int)
 	algorithm := d.Get("algorithm").(int)
 	iterations := d.Get("iterations").(int)
@@ -1178,7 +2277,7 @@ func checkNsec3ParamRecord(d *schema.ResourceData) error {
 	return nil
 }
 
-func checkRpRecord(d *schema.ResourceData) error {
+func checkRpRecord(d resourceGetter) error {
 	mailbox := d.Get("mailbox").(string)
 	txt := d.Get("txt").(string)
 
@@ -1197,7 +2296,7 @@ func checkRpRecord(d *schema.ResourceData) error {
 	return nil
 }
 
-func checkRrsigRecord(d *schema.ResourceData) error {
+func checkRrsigRecord(d resourceGetter) error {
 	expiration := d.Get("expiration").(string)
 	inception := d.Get("inception").(string)
 	originalTTL := d.Get("original_ttl").(int)
@@ -1252,7 +2351,7 @@ func checkRrsigRecord(d *schema.ResourceData) error {
 	return nil
 }
 
-func checkSrvRecord(d *schema.ResourceData) error {
+func checkSrvRecord(d resourceGetter) error {
 	priority := d.Get("priority").(int)
 	weight := d.Get("weight").(int)
 	port := d.Get("port").(int)
@@ -1265,6 +2364,10 @@ func checkSrvRecord(d *schema.ResourceData) error {
 		return err
 	}
 
+	if targetsHaveRdata(d) {
+		return nil
+	}
+
 	if priority == 0 {
 		return fmt.Errorf("Type priority must be set for SRV.")
 	}
@@ -1280,7 +2383,7 @@ func checkSrvRecord(d *schema.ResourceData) error {
 	return nil
 }
 
-func checkSshfpRecord(d *schema.ResourceData) error {
+func checkSshfpRecord(d resourceGetter) error {
 	algorithm := d.Get("algorithm").(int)
 	fingerprintType := d.Get("fingerprint_type").(int)
 	fingerprint := d.Get("fingerprint").(string)
@@ -1304,6 +2407,182 @@ func checkSshfpRecord(d *schema.ResourceData) error {
 	return nil
 }
 
+func checkCaaRecord(d resourceGetter) error {
+	flags := d.Get("flags").(int)
+	tag := d.Get("tag").(string)
+
+	if err := checkBasicRecordTypes(d); err != nil {
+		return err
+	}
+
+	if flags < 0 || flags > 255 {
+		return fmt.Errorf("Type flags must not be %v for CAA.", flags)
+	}
+
+	if tag == "" && !targetsHaveRdata(d) {
+		return fmt.Errorf("Type tag must be set for CAA.")
+	}
+
+	for _, recContent := range d.Get("target").(*schema.Set).List() {
+		if !targetHasRdata(RRTypeCaa, recContent.(string)) {
+			continue
+		}
+		fields := strings.Fields(recContent.(string))
+		if f, _ := strconv.Atoi(fields[0]); f > 255 {
+			return fmt.Errorf("Target %q flags must not be %v for CAA.", recContent, f)
+		}
+		if t := strings.ToLower(fields[1]); t != "issue" && t != "issuewild" && t != "iodef" {
+			return fmt.Errorf("Target %q tag must be issue, issuewild or iodef for CAA.", recContent)
+		}
+	}
+
+	if err := checkTargets(d); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func checkTlsaRecord(d resourceGetter) error {
+	usage := d.Get("usage").(int)
+	selector := d.Get("selector").(int)
+	matchType := d.Get("match_type").(int)
+	certificate := d.Get("certificate").(string)
+
+	if err := checkBasicRecordTypes(d); err != nil {
+		return err
+	}
+
+	if usage < 0 || usage > 3 {
+		return fmt.Errorf("Type usage must not be %v for TLSA.", usage)
+	}
+
+	if selector < 0 || selector > 1 {
+		return fmt.Errorf("Type selector must not be %v for TLSA.", selector)
+	}
+
+	if matchType < 0 || matchType > 2 {
+		return fmt.Errorf("Type match_type must not be %v for TLSA.", matchType)
+	}
+
+	if certificate == "" {
+		return fmt.Errorf("Type certificate must be set for TLSA.")
+	}
+
+	if _, err := hex.DecodeString(certificate); err != nil {
+		return fmt.Errorf("Type certificate must be hex encoded for TLSA.")
+	}
+
+	return nil
+}
+
+func checkCertRecord(d resourceGetter) error {
+	typeMnemonic := d.Get("type_mnemonic").(string)
+	typeValue := d.Get("type_value").(int)
+	certificate := d.Get("certificate").(string)
+
+	if err := checkBasicRecordTypes(d); err != nil {
+		return err
+	}
+
+	if typeMnemonic == "" && typeValue == 0 {
+		return fmt.Errorf("Type type_mnemonic or type_value must be set for CERT.")
+	}
+
+	if typeMnemonic != "" && typeValue != 0 {
+		return fmt.Errorf("Only one of type_mnemonic and type_value may be set for CERT.")
+	}
+
+	if certificate == "" {
+		return fmt.Errorf("Type certificate must be set for CERT.")
+	}
+
+	return nil
+}
+
+func checkSoaRecord(d resourceGetter) error {
+	nameServer := d.Get("name_server").(string)
+	emailAddress := d.Get("email_address").(string)
+	refresh := d.Get("refresh").(int)
+	retry := d.Get("retry").(int)
+	expiry := d.Get("expiry").(int)
+	nxdomainTTL := d.Get("nxdomain_ttl").(int)
+
+	if err := checkBasicRecordTypes(d); err != nil {
+		return err
+	}
+
+	if nameServer == "" {
+		return fmt.Errorf("Type name_server must be set for SOA.")
+	}
+
+	if emailAddress == "" {
+		return fmt.Errorf("Type email_address must be set for SOA.")
+	}
+
+	if refresh == 0 {
+		return fmt.Errorf("Type refresh must be set for SOA.")
+	}
+
+	if retry == 0 {
+		return fmt.Errorf("Type retry must be set for SOA.")
+	}
+
+	if expiry == 0 {
+		return fmt.Errorf("Type expiry must be set for SOA.")
+	}
+
+	if nxdomainTTL == 0 {
+		return fmt.Errorf("Type nxdomain_ttl must be set for SOA.")
+	}
+
+	return nil
+}
+
+func checkSvcbRecord(d resourceGetter) error {
+	recordtype := d.Get("recordtype").(string)
+	svcPriority := d.Get("svc_priority").(int)
+	targetName := d.Get("target_name").(string)
+	svcParams := d.Get("svc_params").(string)
+
+	if err := checkBasicRecordTypes(d); err != nil {
+		return err
+	}
+
+	if svcPriority < 0 || svcPriority > 65535 {
+		return fmt.Errorf("Type svc_priority must not be %v for %s.", svcPriority, recordtype)
+	}
+
+	if targetName == "" {
+		return fmt.Errorf("Type target_name must be set for %s.", recordtype)
+	}
+
+	if svcPriority == 0 && svcParams != "" {
+		return fmt.Errorf("Type svc_params must not be set for %s in alias mode (svc_priority 0).", recordtype)
+	}
+
+	return nil
+}
+
+func checkAkamaiTlcRecord(d resourceGetter) error {
+	answerType := d.Get("answer_type").(string)
+	dnsName := d.Get("dns_name").(string)
+
+	if err := checkBasicRecordTypes(d); err != nil {
+		return err
+	}
+
+	if answerType == "" {
+		return fmt.Errorf("Type answer_type must be set for AKAMAITLC.")
+	}
+
+	if dnsName == "" {
+		return fmt.Errorf("Type dns_name must be set for AKAMAITLC.")
+	}
+
+	return nil
+}
+
 // Resource record types supported by the Akamai Edge DNS API
 const (
 	RRTypeA          = "A"
@@ -1312,17 +2591,21 @@ const (
 	RRTypeAkamaiCdn  = "AKAMAICDN"
 	RRTypeAkamaiTlc  = "AKAMAITLC"
 	RRTypeCaa        = "CAA"
+	RRTypeCert       = "CERT"
 	RRTypeCname      = "CNAME"
 	RRTypeHinfo      = "HINFO"
+	RRTypeHttps      = "HTTPS"
 	RRTypeLoc        = "LOC"
 	RRTypeMx         = "MX"
 	RRTypeNaptr      = "NAPTR"
 	RRTypeNs         = "NS"
 	RRTypePtr        = "PTR"
 	RRTypeRp         = "RP"
+	RRTypeSoa        = "SOA"
 	RRTypeSrv        = "SRV"
 	RRTypeSpf        = "SPF"
 	RRTypeSshfp      = "SSHFP"
+	RRTypeSvcb       = "SVCB"
 	RRTypeTlsa       = "TLSA"
 	RRTypeTxt        = "TXT"
 	RRTypeDnskey     = "DNSKEY"
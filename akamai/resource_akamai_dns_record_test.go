@@ -3,12 +3,14 @@ package akamai
 import (
 	"fmt"
 	"log"
+	"reflect"
 
 	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 
-	//"strings"
+	"strings"
 	"testing"
 )
 
@@ -87,8 +89,15 @@ func TestAccAkamaiDNSv2Record_basic(t *testing.T) {
 				Config: testAccAkamaiDNSv2RecordConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckAkamaiDNSv2RecordExists,
+					resource.TestCheckResourceAttr("akamai_dns_record.a_record", "id", "exampleterraform.io/exampleterraform.io/A"),
 				),
 			},
+			{
+				ResourceName:      "akamai_dns_record.a_record",
+				ImportState:       true,
+				ImportStateId:     "exampleterraform.io/exampleterraform.io/A",
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -169,3 +178,220 @@ func testAccCheckAkamaiDNSv2RecordExists(s *terraform.State) error {
 	}
 	return nil
 }
+
+func TestRdataFields(t *testing.T) {
+	cases := []struct {
+		recordtype string
+		rdata      []string
+		expected   map[string]interface{}
+	}{
+		{RRTypeA, []string{"10.0.0.2", "10.0.0.3"}, map[string]interface{}{"target": []interface{}{"10.0.0.2", "10.0.0.3"}}},
+		{RRTypeMx, []string{"10 mx1.example.com.", "10 mx2.example.com."}, map[string]interface{}{"priority": 10, "priority_increment": 0, "target": []interface{}{"mx1.example.com.", "mx2.example.com."}}},
+		{RRTypeMx, []string{"10 mx1.example.com.", "20 mx2.example.com."}, map[string]interface{}{"priority": 0, "priority_increment": 0, "target": []interface{}{"10 mx1.example.com.", "20 mx2.example.com."}}},
+		{RRTypeSrv, []string{"10 60 5060 sip.example.com."}, map[string]interface{}{"priority": 10, "weight": 60, "port": 5060, "target": []interface{}{"sip.example.com."}}},
+		{RRTypeSrv, []string{"10 60 5060 sip1.example.com.", "20 0 5060 sip2.example.com."}, map[string]interface{}{"priority": 0, "weight": 0, "port": 0, "target": []interface{}{"10 60 5060 sip1.example.com.", "20 0 5060 sip2.example.com."}}},
+		{RRTypeAfsdb, []string{"1 afs1.example.com.", "2 afs2.example.com."}, map[string]interface{}{"subtype": 0, "target": []interface{}{"1 afs1.example.com.", "2 afs2.example.com."}}},
+		{RRTypeDs, []string{"60485 1 5 2BB183AF5F22588179A53B0A98631FAD1A292118"}, map[string]interface{}{"keytag": 60485, "digest_type": 1, "algorithm": 5, "digest": "2BB183AF5F22588179A53B0A98631FAD1A292118"}},
+		{RRTypeHinfo, []string{"INTEL-386 Unix"}, map[string]interface{}{"hardware": "INTEL-386", "software": "Unix"}},
+		{RRTypeSshfp, []string{"2 1 123456789abcdef67890123456789abcdef67890"}, map[string]interface{}{"algorithm": 2, "fingerprint_type": 1, "fingerprint": "123456789abcdef67890123456789abcdef67890"}},
+		{RRTypeCaa, []string{`0 issue "letsencrypt.org"`, `0 issue "digicert.com"`}, map[string]interface{}{"flags": 0, "tag": "issue", "target": []interface{}{"letsencrypt.org", "digicert.com"}}},
+		{RRTypeCaa, []string{`0 issue "letsencrypt.org"`, `0 iodef "mailto:security@example.com"`}, map[string]interface{}{"flags": 0, "tag": "", "target": []interface{}{`0 issue "letsencrypt.org"`, `0 iodef "mailto:security@example.com"`}}},
+		{RRTypeTlsa, []string{"3 1 1 0C72AC70B745AC19998811B131D662C9AC69DBDBE7CB23E5B514B56664C5D3D6"}, map[string]interface{}{"usage": 3, "selector": 1, "match_type": 1, "certificate": "0C72AC70B745AC19998811B131D662C9AC69DBDBE7CB23E5B514B56664C5D3D6"}},
+		{RRTypeCert, []string{"PKIX 12 8 MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8A"}, map[string]interface{}{"type_mnemonic": "PKIX", "type_value": 0, "keytag": 12, "algorithm": 8, "certificate": "MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8A"}},
+		{RRTypeSoa, []string{"a1-2.akam.net. hostmaster.example.com. 2020031901 3600 600 604800 300"}, map[string]interface{}{"name_server": "a1-2.akam.net.", "email_address": "hostmaster.example.com.", "refresh": 3600, "retry": 600, "expiry": 604800, "nxdomain_ttl": 300}},
+		{RRTypeHttps, []string{"1 . alpn=h2,h3"}, map[string]interface{}{"svc_priority": 1, "target_name": ".", "svc_params": "alpn=h2,h3"}},
+		{RRTypeSvcb, []string{"0 svc.example.com."}, map[string]interface{}{"svc_priority": 0, "target_name": "svc.example.com.", "svc_params": ""}},
+		{RRTypeAkamaiTlc, []string{"DUALSTACK example.com.edgekey.net"}, map[string]interface{}{"answer_type": "DUALSTACK", "dns_name": "example.com.edgekey.net"}},
+	}
+
+	for _, c := range cases {
+		fields, err := rdataFields(c.recordtype, c.rdata)
+		if err != nil {
+			t.Errorf("Value %v is invalid: %v", c.rdata, err)
+			continue
+		}
+		if !reflect.DeepEqual(fields, c.expected) {
+			t.Errorf("rdataFields(%s, %v) = %v, expected %v", c.recordtype, c.rdata, fields, c.expected)
+		}
+	}
+
+	if _, err := rdataFields(RRTypeMx, []string{"mx1.example.com."}); err == nil {
+		t.Error("expected an error for MX rdata without a priority")
+	}
+}
+
+func TestParseDNSRecordID(t *testing.T) {
+	zone, host, recordtype, err := parseDNSRecordID("example-zone.com/www.example-zone.com/cname")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if zone != "example-zone.com" || host != "www.example-zone.com" || recordtype != "CNAME" {
+		t.Errorf("unexpected ID parts %s, %s, %s", zone, host, recordtype)
+	}
+
+	for _, id := range []string{"example.com-www.example.com-A-abc123", "example.com/www.example.com", "/www.example.com/A"} {
+		if _, _, _, err := parseDNSRecordID(id); err == nil {
+			t.Errorf("Value %v should be invalid", id)
+		}
+	}
+}
+
+func TestResourceDNSv2RecordStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":         "example-zone.com-www.example-zone.com-A-0fd1b9c8",
+		"zone":       "example-zone.com",
+		"name":       "www.example-zone.com",
+		"recordtype": "A",
+	}
+
+	upgraded, err := resourceDNSv2RecordStateUpgradeV0(rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if upgraded["id"] != "example-zone.com/www.example-zone.com/A" {
+		t.Errorf("unexpected upgraded ID %v", upgraded["id"])
+	}
+}
+
+func TestResourceDNSv2RecordV0Schema(t *testing.T) {
+	v0 := resourceDNSv2RecordV0().Schema
+	for _, k := range []string{"zone", "name", "recordtype", "ttl", "active", "target", "priority", "priority_increment", "weight", "port", "subtype", "flags", "key", "digest", "signature", "fingerprint"} {
+		if _, ok := v0[k]; !ok {
+			t.Errorf("Value %v is missing from the version 0 schema", k)
+		}
+	}
+	if v0["target"].Type != schema.TypeSet || v0["ttl"].Type != schema.TypeInt || v0["key"].Type != schema.TypeString {
+		t.Errorf("Value %v is invalid: the version 0 attribute types must not change", v0)
+	}
+	for _, k := range []string{"stage", "tag", "certificate", "svc_params", "answer_type", "dns_name"} {
+		if _, ok := v0[k]; ok {
+			t.Errorf("Value %v is invalid: added after version 0", k)
+		}
+	}
+	if _, ok := akamaiDNSv2RecordSchema["stage"]; !ok {
+		t.Errorf("Value %v is missing from the current schema", "stage")
+	}
+}
+
+func TestNormalizeRdata(t *testing.T) {
+	cases := []struct {
+		recordtype string
+		rdata      string
+		expected   string
+	}{
+		{RRTypeCaa, "0 ISSUE letsencrypt.org", `0 issue "letsencrypt.org"`},
+		{RRTypeCaa, `0 issue "letsencrypt.org"`, `0 issue "letsencrypt.org"`},
+		{RRTypeTlsa, "3 1 1 0c72ac70b745ac19 998811b131d662c9", "3 1 1 0C72AC70B745AC19998811B131D662C9"},
+		{RRTypeSoa, "a1-2.akam.net hostmaster.example.com 1 3600 600 604800 300", "a1-2.akam.net. hostmaster.example.com. 1 3600 600 604800 300"},
+		{RRTypeHttps, "1  svc.example.com  alpn=h2", "1 svc.example.com. alpn=h2"},
+		{RRTypeA, "10.0.0.1", "10.0.0.1"},
+		{RRTypeTxt, "v=spf1 -all", `"v=spf1 -all"`},
+		{RRTypeTxt, `"v=spf1 " "-all"`, `"v=spf1 -all"`},
+		{RRTypeTxt, `say "hi"`, `"say \"hi\""`},
+		{RRTypeSpf, "v=spf1 -all", `"v=spf1 -all"`},
+	}
+
+	for _, c := range cases {
+		if got := normalizeRdata(c.recordtype, c.rdata); got != c.expected {
+			t.Errorf("normalizeRdata(%s, %q) = %q, expected %q", c.recordtype, c.rdata, got, c.expected)
+		}
+	}
+
+	soa := comparableRdata(RRTypeSoa, []string{"a1-2.akam.net. hostmaster.example.com. 2020031901 3600 600 604800 300"})
+	if soa[0] != "a1-2.akam.net. hostmaster.example.com. 0 3600 600 604800 300" {
+		t.Errorf("SOA serial should be ignored, got %q", soa[0])
+	}
+}
+
+func TestTxtRdataChunks(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	expected := `"` + strings.Repeat("a", 255) + `" "` + strings.Repeat("a", 45) + `"`
+	if got := txtRdata(long); got != expected {
+		t.Errorf("Value %v is invalid: %v", long, got)
+	}
+	if got := txtRdata(expected); got != expected {
+		t.Errorf("Value %v is invalid: %v", expected, got)
+	}
+
+	escaped := strings.Repeat("a", 254) + `\"b`
+	if got := txtRdata(escaped); got != `"`+strings.Repeat("a", 254)+`\"" "b"` {
+		t.Errorf("Value %v is invalid: %v", escaped, got)
+	}
+}
+
+func TestCanonicalTargets(t *testing.T) {
+	cases := []struct {
+		recordtype string
+		old        []interface{}
+		new        []interface{}
+		equivalent bool
+	}{
+		{RRTypeAaaa, []interface{}{"2001:0db8:0000:0000:0000:0000:0000:0001"}, []interface{}{"2001:db8::1"}, true},
+		{RRTypeCname, []interface{}{"www.example.com."}, []interface{}{"www.example.com"}, true},
+		{RRTypeTxt, []interface{}{`"v=spf1 -all"`}, []interface{}{"v=spf1 -all"}, true},
+		{RRTypeLoc, []interface{}{"52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000.00m 10.00m"}, []interface{}{"52 22 23.000 N 4 53 32.000 E -2m 0m 10000m 10m"}, true},
+		{RRTypeA, []interface{}{"10.0.0.1"}, []interface{}{"10.0.0.2"}, false},
+		{RRTypeTxt, []interface{}{"abc."}, []interface{}{"abc"}, false},
+	}
+
+	for _, c := range cases {
+		o := strings.Join(canonicalTargets(c.recordtype, schema.NewSet(schema.HashString, c.old)), "\n")
+		n := strings.Join(canonicalTargets(c.recordtype, schema.NewSet(schema.HashString, c.new)), "\n")
+		if (o == n) != c.equivalent {
+			t.Errorf("Value %v is invalid: %v vs %v", c.new, o, n)
+		}
+	}
+}
+
+func TestBindRecordMx(t *testing.T) {
+	d := resourceDNSv2Record().TestResourceData()
+	d.Set("zone", "example.com")
+	d.Set("name", "example.com")
+	d.Set("recordtype", RRTypeMx)
+	d.Set("ttl", 300)
+	d.Set("priority", 10)
+	d.Set("target", []interface{}{"mx1.example.com"})
+
+	record := bindRecord(d)
+	if !reflect.DeepEqual(record.Target, []string{"10 mx1.example.com."}) {
+		t.Errorf("Value %v is invalid: only the configured targets should be bound", record.Target)
+	}
+
+	// Targets that carry their own priority keep it, so imported records
+	// with mixed priorities are written back unchanged.
+	d.Set("priority", 0)
+	d.Set("target", []interface{}{"10 mx1.example.com.", "20 mx2.example.com"})
+	record = bindRecord(d)
+	if !reflect.DeepEqual(record.Target, []string{"10 mx1.example.com.", "20 mx2.example.com."}) {
+		t.Errorf("Value %v is invalid: the target priorities should be kept", record.Target)
+	}
+}
+
+func TestValidateRecord(t *testing.T) {
+	valid := []map[string]interface{}{
+		{"zone": "example.com", "name": "www.example.com", "recordtype": RRTypeA, "ttl": 300, "active": true, "target": []interface{}{"10.0.0.1"}},
+		{"zone": "example.com", "name": "www.example.com", "recordtype": RRTypeAaaa, "ttl": 300, "active": true, "target": []interface{}{"2001:db8::1"}},
+		{"zone": "example.com", "name": "example.com", "recordtype": RRTypeMx, "ttl": 300, "active": true, "target": []interface{}{"mail.example.com"}, "priority": 10},
+		{"zone": "example.com", "name": "example.com", "recordtype": RRTypeCaa, "ttl": 300, "active": true, "target": []interface{}{`0 issue "letsencrypt.org"`, `0 iodef "mailto:security@example.com"`}},
+	}
+	invalid := []map[string]interface{}{
+		{"zone": "example.com", "name": "www.example.com", "recordtype": RRTypeA, "ttl": 300, "active": true, "target": []interface{}{"2001:db8::1"}},
+		{"zone": "example.com", "name": "www.example.com", "recordtype": RRTypeAaaa, "ttl": 300, "active": true, "target": []interface{}{"10.0.0.1"}},
+		{"zone": "example.com", "name": "www.example.com", "recordtype": RRTypeCname, "ttl": 300, "active": true, "target": []interface{}{"a.example.com", "b.example.com"}},
+		{"zone": "example.com", "name": "loc.example.com", "recordtype": RRTypeLoc, "ttl": 300, "active": true, "target": []interface{}{"52 22 23.000 N"}},
+		{"zone": "example.com", "name": "www.example.com", "recordtype": RRTypeA, "ttl": 300, "active": true},
+		{"zone": "example.com", "name": "example.com", "recordtype": RRTypeCaa, "ttl": 300, "active": true, "target": []interface{}{"letsencrypt.org"}},
+		{"zone": "example.com", "name": "example.com", "recordtype": RRTypeCaa, "ttl": 300, "active": true, "target": []interface{}{`0 issuer "letsencrypt.org"`}},
+	}
+
+	for _, v := range valid {
+		if err := validateRecord(schema.TestResourceDataRaw(t, akamaiDNSv2RecordSchema, v)); err != nil {
+			t.Errorf("Value %v is invalid: %v", v, err)
+		}
+	}
+	for _, v := range invalid {
+		if err := validateRecord(schema.TestResourceDataRaw(t, akamaiDNSv2RecordSchema, v)); err == nil {
+			t.Errorf("Value %v should be invalid", v)
+		}
+	}
+}
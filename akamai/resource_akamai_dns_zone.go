@@ -1,25 +1,25 @@
 package akamai
 
 import (
-	"encoding/json"
+	"encoding/base64"
 	"fmt"
 	"log"
 	"strings"
-	"sync"
+	"time"
 
 	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
-var dnsWriteLock sync.Mutex
-
 func resourceDNSv2Zone() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceDNSv2ZoneCreate,
-		Read:   resourceDNSv2ZoneRead,
-		Update: resourceDNSv2ZoneUpdate,
-		Delete: resourceDNSv2ZoneDelete,
-		Exists: resourceDNSv2ZoneExists,
+		Create:        resourceDNSv2ZoneCreate,
+		Read:          resourceDNSv2ZoneRead,
+		Update:        resourceDNSv2ZoneUpdate,
+		Delete:        resourceDNSv2ZoneDelete,
+		Exists:        resourceDNSv2ZoneExists,
+		CustomizeDiff: resourceDNSv2ZoneCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: resourceDNSv2ZoneImport,
 		},
@@ -35,7 +35,6 @@ func resourceDNSv2Zone() *schema.Resource {
 			"type": {
 				Type:         schema.TypeString,
 				Required:     true,
-				ForceNew:     true,
 				ValidateFunc: validateZoneType,
 				StateFunc: func(val interface{}) string {
 					return strings.ToUpper(val.(string))
@@ -60,6 +59,83 @@ func resourceDNSv2Zone() *schema.Resource {
 				Type:     schema.TypeBool,
 				Required: true,
 			},
+			"target": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tsig_key": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"algorithm": {
+							Type:     schema.TypeString,
+							Required: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"hmac-md5.sig-alg.reg.int",
+								"hmac-sha1",
+								"hmac-sha224",
+								"hmac-sha256",
+								"hmac-sha384",
+								"hmac-sha512",
+							}, true),
+						},
+						"secret": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Sensitive:    true,
+							ValidateFunc: validateTSIGSecret,
+						},
+					},
+				},
+			},
+			"end_customer_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"sign_and_serve_algorithm": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"RSA_SHA1",
+					"RSA_SHA256",
+					"RSA_SHA512",
+					"ECDSA_P256_SHA256",
+					"ECDSA_P384_SHA384",
+				}, true),
+				StateFunc: func(val interface{}) string {
+					return strings.ToUpper(val.(string))
+				},
+			},
+			"key_rollover_trigger": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"version_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"force_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"master_file": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressEquivalentZoneFiles,
+			},
+			"master_file_records": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
 		},
 	}
 }
@@ -71,27 +147,17 @@ func resourceDNSv2ZoneCreate(d *schema.ResourceData, meta interface{}) error {
 	// in your config.tf which might overwrite each other
 
 	hostname := d.Get("zone").(string)
-	zonetype := d.Get("type").(string)
+	zonetype := strings.ToUpper(d.Get("type").(string))
 	masterlist := d.Get("masters").(*schema.Set).List()
-	masters := make([]string, 0, len(masterlist))
 
 	if zonetype == "SECONDARY" && len(masterlist) == 0 {
 		return fmt.Errorf("DNS Secondary zone requires masters for zone %v", hostname)
 	}
 
-	if len(masterlist) > 0 {
-		for _, master := range masterlist {
-			masters = append(masters, master.(string))
-		}
-
-	}
-
-	comment := d.Get("comment").(string)
-	signandserve := d.Get("sign_and_serve").(bool)
 	contract := strings.TrimPrefix(d.Get("contract").(string), "ctr_")
 	group := strings.TrimPrefix(d.Get("group").(string), "grp_")
 	zonequerystring := dnsv2.ZoneQueryString{Contract: contract, Group: group}
-	zonecreate := dnsv2.ZoneCreate{Zone: hostname, Type: zonetype, Masters: masters, Comment: comment, SignAndServe: signandserve}
+	zonecreate := expandDNSZone(d)
 
 	// First try to get the zone from the API
 	log.Printf("[DEBUG] [Akamai DNSv2] Searching for zone [%s]", hostname)
@@ -106,18 +172,27 @@ func resourceDNSv2ZoneCreate(d *schema.ResourceData, meta interface{}) error {
 			// blank zone for the records to be added to and continue
 			log.Printf("[DEBUG] [Akamai DNS] [ERROR] %s", e.Error())
 			log.Printf("[DEBUG] [Akamai DNS] Creating new zone")
-			e = zonecreate.Save(zonequerystring)
+			e = createZone(zonecreate, zonequerystring)
 			if e != nil {
 				return e
 			}
 
-			e = zonecreate.SaveChangelist()
-			if e != nil {
-				return e
+			// Only primary zones have records; the default SOA and NS
+			// records are created by submitting an empty changelist
+			if zonetype == "PRIMARY" {
+				changelist := dnsv2.ZoneCreate{Zone: hostname}
+				e = changelist.SaveChangelist()
+				if e != nil {
+					return e
+				}
+
+				e = changelist.SubmitChangelist()
+				if e != nil {
+					return e
+				}
 			}
 
-			e = zonecreate.SubmitChangelist()
-			if e != nil {
+			if e = uploadMasterFile(d); e != nil {
 				return e
 			}
 
@@ -134,6 +209,10 @@ func resourceDNSv2ZoneCreate(d *schema.ResourceData, meta interface{}) error {
 
 	// Save the zone to the API
 	log.Printf("[DEBUG] [Akamai DNSv2] Updating zone %v", zonecreate)
+	if e = uploadMasterFile(d); e != nil {
+		return e
+	}
+
 	// Give terraform the ID
 	d.SetId(fmt.Sprintf("%s-%s-%s", zone.VersionId, zone.Zone, hostname))
 	return resourceDNSv2ZoneRead(d, meta)
@@ -159,13 +238,23 @@ func resourceDNSv2ZoneRead(d *schema.ResourceData, meta interface{}) error {
 	}
 	// find the zone first
 	log.Printf("[INFO] [Akamai DNS] Searching for zone [%s]", hostname)
-	zone, err := dnsv2.GetZone(hostname)
+	zone, err := getZone(hostname)
 	if err != nil {
 		return err
 	}
 
 	log.Printf("[DEBUG] [Akamai DNSv2] READ %v", zone)
-	d.SetId(fmt.Sprintf("%s-%s-%s", zone.VersionId, zone.Zone, hostname))
+	d.Set("end_customer_id", zone.EndCustomerID)
+	d.Set("sign_and_serve_algorithm", zone.SignAndServeAlgorithm)
+	d.Set("version_id", zone.VersionID)
+
+	if masterfile, ok := d.GetOk("master_file"); ok {
+		if err := readMasterFile(d, hostname, masterfile.(string)); err != nil {
+			return err
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s-%s-%s", zone.VersionID, zone.Zone, hostname))
 	return nil
 }
 
@@ -176,29 +265,8 @@ func resourceDNSv2ZoneUpdate(d *schema.ResourceData, meta interface{}) error {
 	// in your config.tf which might overwrite each other
 
 	hostname := d.Get("zone").(string)
-	contract := d.Get("contract").(string)
-	zonetype := d.Get("type").(string)
-	masterlist := d.Get("masters").(*schema.Set).List()
-	masters := make([]string, 0, len(masterlist))
-	if len(masterlist) > 0 {
-		for _, master := range masterlist {
-			masters = append(masters, master.(string))
-		}
-
-	}
-	comment := d.Get("comment").(string)
-	group := d.Get("group").(string)
-	signandserve := d.Get("sign_and_serve").(bool)
-	zonequerystring := dnsv2.ZoneQueryString{Contract: contract, Group: group}
-	zonecreate := dnsv2.ZoneCreate{Zone: hostname, Type: zonetype, Masters: masters, Comment: comment, SignAndServe: signandserve}
+	zonecreate := expandDNSZone(d)
 
-	b, err := json.Marshal(zonecreate)
-	if err != nil {
-		fmt.Println(err)
-		return err
-	}
-	fmt.Println(string(b))
-	log.Printf("[DEBUG] [Akamai DNSv2] Searching for zone %s", string(b))
 	// First try to get the zone from the API
 	log.Printf("[DEBUG] [Akamai DNSv2] Searching for zone [%s]", hostname)
 	log.Printf("[DEBUG] [Akamai DNSv2] Searching for zone [%v]", zonecreate)
@@ -219,11 +287,32 @@ func resourceDNSv2ZoneUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
-	// Save the zone to the API
-	log.Printf("[DEBUG] [Akamai DNSv2] Saving zone %v", zonecreate)
-	e = zonecreate.Update(zonequerystring)
-	if e != nil {
-		return e
+	// A secondary zone converted to primary keeps the records transferred
+	// from its masters, unless a master file replaces them
+	if o, n := d.GetChange("type"); isZoneConversion(o.(string), n.(string)) {
+		if e = convertSecondaryZone(d, zonecreate); e != nil {
+			return e
+		}
+	} else {
+		// Save the zone to the API
+		log.Printf("[DEBUG] [Akamai DNSv2] Saving zone %v", zonecreate)
+		e = updateZone(zonecreate)
+		if e != nil {
+			return e
+		}
+
+		if d.HasChange("master_file") {
+			if e = uploadMasterFile(d); e != nil {
+				return e
+			}
+		}
+	}
+
+	if d.HasChange("key_rollover_trigger") && d.Get("sign_and_serve").(bool) {
+		log.Printf("[DEBUG] [Akamai DNSv2] Starting key rollover for zone [%s]", hostname)
+		if e = rotateZoneKeys(hostname); e != nil {
+			return fmt.Errorf("unable to start key rollover for zone %s: %s", hostname, e)
+		}
 	}
 
 	// Give terraform the ID
@@ -261,9 +350,40 @@ func resourceDNSv2ZoneImport(d *schema.ResourceData, meta interface{}) ([]*schem
 func resourceDNSv2ZoneDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Deleting DNS Zone")
 
-	// No ZONE delete operation permitted.
+	hostname := d.Get("zone").(string)
+	zonetype := strings.ToUpper(d.Get("type").(string))
+	forcedestroy := d.Get("force_destroy").(bool)
+
+	// Like a non-empty bucket, a primary zone with records of its own is only
+	// deleted when force_destroy is set
+	if zonetype == "PRIMARY" && !forcedestroy {
+		recordsets, err := getZoneRecordsets(hostname)
+		if err != nil {
+			if isNotFoundError(err) {
+				d.SetId("")
+				return nil
+			}
+			return err
+		}
+
+		var records []string
+		for _, rs := range recordsets {
+			if !isZoneApexRecordset(hostname, rs) {
+				records = append(records, fmt.Sprintf("%s %s", rs.Name, rs.Type))
+			}
+		}
+		if len(records) > 0 {
+			return fmt.Errorf("zone %s still has %d recordsets (%s), set force_destroy to delete the zone and its records", hostname, len(records), strings.Join(records, ", "))
+		}
+	}
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Deleting zone [%s]", hostname)
+	if err := deleteZone(hostname, forcedestroy); err != nil {
+		return err
+	}
 
-	return schema.Noop(d, meta)
+	d.SetId("")
+	return nil
 }
 
 func resourceDNSv2ZoneExists(d *schema.ResourceData, meta interface{}) (bool, error) {
@@ -289,6 +409,257 @@ func resourceDNSv2ZoneExists(d *schema.ResourceData, meta interface{}) (bool, er
 	return zone != nil, err
 }
 
+// resourceDNSv2ZoneCustomizeDiff validates the settings of the zone type and
+// master_file at plan time, and shows the records master_file will change.
+func resourceDNSv2ZoneCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	hostname := d.Get("zone").(string)
+	zonetype := strings.ToUpper(d.Get("type").(string))
+
+	// Zones are never replaced, as deleting a zone would take it off the air
+	if d.Id() != "" {
+		if o, n := d.GetChange("type"); o.(string) != "" && !strings.EqualFold(o.(string), n.(string)) && !isZoneConversion(o.(string), n.(string)) {
+			return fmt.Errorf("zone %s can not be changed from %s to %s in place, only SECONDARY zones can be converted to PRIMARY", hostname, o, strings.ToUpper(n.(string)))
+		}
+		for _, k := range []string{"contract", "group"} {
+			o, n := d.GetChange(k)
+			if o.(string) != "" && d.NewValueKnown(k) && trimZoneQueryPrefix(o.(string)) != trimZoneQueryPrefix(n.(string)) {
+				return fmt.Errorf("zone %s can not be moved from %s %s to %s: moving zones is not supported by this provider, move the zone in Control Center and update the configuration to match", hostname, k, o, n)
+			}
+		}
+	}
+
+	if target, ok := d.GetOk("target"); ok && zonetype != "ALIAS" {
+		return fmt.Errorf("target %s can only be set for ALIAS zones, zone %s is %s", target, hostname, zonetype)
+	}
+	if zonetype == "ALIAS" && d.NewValueKnown("target") && d.Get("target").(string) == "" {
+		return fmt.Errorf("DNS Alias zone requires target for zone %s", hostname)
+	}
+	if _, ok := d.GetOk("tsig_key"); ok && zonetype != "SECONDARY" {
+		return fmt.Errorf("tsig_key can only be set for SECONDARY zones, zone %s is %s", hostname, zonetype)
+	}
+	if !d.Get("sign_and_serve").(bool) {
+		if d.HasChange("sign_and_serve_algorithm") && d.Get("sign_and_serve_algorithm").(string) != "" {
+			return fmt.Errorf("sign_and_serve_algorithm requires sign_and_serve for zone %s", hostname)
+		}
+		if d.HasChange("key_rollover_trigger") && d.Get("key_rollover_trigger").(string) != "" {
+			return fmt.Errorf("key_rollover_trigger requires sign_and_serve for zone %s", hostname)
+		}
+	}
+
+	if !d.NewValueKnown("master_file") {
+		return nil
+	}
+	masterfile, ok := d.GetOk("master_file")
+	if !ok {
+		return nil
+	}
+
+	if zonetype != "PRIMARY" {
+		return fmt.Errorf("master_file can only be set for PRIMARY zones, zone %s is %s", hostname, zonetype)
+	}
+
+	recordsets, err := parseZoneFile(hostname, masterfile.(string))
+	if err != nil {
+		return fmt.Errorf("invalid master_file for zone %s: %s", hostname, err)
+	}
+
+	if d.HasChange("master_file") {
+		return d.SetNew("master_file_records", zoneFileRecords(recordsets))
+	}
+	return nil
+}
+
+// zoneConversionAttempts is how many times the records of a converted zone
+// are uploaded before the conversion is rolled back.
+const zoneConversionAttempts = 3
+
+// convertSecondaryZone converts a secondary zone to primary and uploads its
+// records, either the master_file or a snapshot of the records transferred
+// from the masters. The zone is empty between the two steps, so the upload
+// is retried and, when it keeps failing, the zone is converted back to
+// SECONDARY with its previous masters to resume transfers.
+func convertSecondaryZone(d *schema.ResourceData, zonecreate *dnsZone) error {
+	hostname := zonecreate.Zone
+
+	records, ok := d.GetOk("master_file")
+	if !ok {
+		log.Printf("[DEBUG] [Akamai DNSv2] Snapshotting records of zone [%s] before converting it to PRIMARY", hostname)
+		snapshot, err := dnsv2.GetMasterZoneFile(hostname)
+		if err != nil {
+			return fmt.Errorf("unable to snapshot records of zone %s before converting it to PRIMARY: %s", hostname, err)
+		}
+		records = snapshot
+	}
+
+	log.Printf("[DEBUG] [Akamai DNSv2] Converting zone %v", zonecreate)
+	if err := updateZone(zonecreate); err != nil {
+		return err
+	}
+
+	var err error
+	for attempt := 1; attempt <= zoneConversionAttempts; attempt++ {
+		log.Printf("[DEBUG] [Akamai DNSv2] Uploading records of converted zone [%s], attempt %d", hostname, attempt)
+		err = withZoneLock(hostname, func() error {
+			return uploadZoneFile(hostname, records.(string))
+		})
+		if err == nil {
+			return nil
+		}
+		log.Printf("[WARN] [Akamai DNSv2] Unable to upload records of converted zone [%s]: %s", hostname, err)
+		if attempt < zoneConversionAttempts {
+			time.Sleep(dnsConflictInterval)
+		}
+	}
+
+	original := *zonecreate
+	original.Type = "SECONDARY"
+	original.Masters = nil
+	oldmasters, _ := d.GetChange("masters")
+	for _, master := range oldmasters.(*schema.Set).List() {
+		original.Masters = append(original.Masters, master.(string))
+	}
+	original.TsigKey = nil
+	if oldkeys, _ := d.GetChange("tsig_key"); len(oldkeys.([]interface{})) > 0 && oldkeys.([]interface{})[0] != nil {
+		key := oldkeys.([]interface{})[0].(map[string]interface{})
+		original.TsigKey = &dnsTSIGKey{
+			Name:      key["name"].(string),
+			Algorithm: key["algorithm"].(string),
+			Secret:    key["secret"].(string),
+		}
+	}
+	if rerr := updateZone(&original); rerr != nil {
+		return fmt.Errorf("zone %s was converted to PRIMARY but its records could not be uploaded (%s), and converting it back to SECONDARY failed, the zone has no records: %s", hostname, err, rerr)
+	}
+	return fmt.Errorf("unable to upload the records of zone %s after converting it to PRIMARY, the zone was converted back to SECONDARY: %s", hostname, err)
+}
+
+// isZoneConversion reports whether a zone type change converts a secondary
+// zone to a primary zone, the only type change made in place.
+func isZoneConversion(oldtype string, newtype string) bool {
+	return strings.EqualFold(oldtype, "SECONDARY") && strings.EqualFold(newtype, "PRIMARY")
+}
+
+// trimZoneQueryPrefix returns a contract or group ID without its ctr_ or grp_ prefix.
+func trimZoneQueryPrefix(id string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(id, "ctr_"), "grp_")
+}
+
+// expandDNSZone builds the API representation of the zone from its configuration.
+func expandDNSZone(d *schema.ResourceData) *dnsZone {
+	masterlist := d.Get("masters").(*schema.Set).List()
+	masters := make([]string, 0, len(masterlist))
+	for _, master := range masterlist {
+		masters = append(masters, master.(string))
+	}
+
+	zone := &dnsZone{
+		Zone:         d.Get("zone").(string),
+		Type:         strings.ToUpper(d.Get("type").(string)),
+		Masters:      masters,
+		Comment:      d.Get("comment").(string),
+		SignAndServe: d.Get("sign_and_serve").(bool),
+		Target:       d.Get("target").(string),
+	}
+	if zone.SignAndServe {
+		zone.SignAndServeAlgorithm = strings.ToUpper(d.Get("sign_and_serve_algorithm").(string))
+	}
+
+	if keys := d.Get("tsig_key").([]interface{}); len(keys) > 0 && keys[0] != nil {
+		key := keys[0].(map[string]interface{})
+		zone.TsigKey = &dnsTSIGKey{
+			Name:      key["name"].(string),
+			Algorithm: key["algorithm"].(string),
+			Secret:    key["secret"].(string),
+		}
+	}
+
+	return zone
+}
+
+// uploadMasterFile uploads the master_file of a zone, if one is configured.
+func uploadMasterFile(d *schema.ResourceData) error {
+	masterfile, ok := d.GetOk("master_file")
+	if !ok {
+		return nil
+	}
+
+	hostname := d.Get("zone").(string)
+	log.Printf("[DEBUG] [Akamai DNSv2] Uploading master file for zone [%s]", hostname)
+	err := withZoneLock(hostname, func() error {
+		return uploadZoneFile(hostname, masterfile.(string))
+	})
+	if err != nil {
+		return fmt.Errorf("unable to upload master file for zone %s: %s", hostname, err)
+	}
+	return nil
+}
+
+// readMasterFile compares the live zone file with the configured one and
+// stores the live file when its records differ. SOA and apex NS records the
+// configured file does not declare are managed by Edge DNS and ignored.
+func readMasterFile(d *schema.ResourceData, hostname string, masterfile string) error {
+	live, err := dnsv2.GetMasterZoneFile(hostname)
+	if err != nil {
+		return err
+	}
+
+	liverecordsets, err := parseZoneFile(hostname, live)
+	if err != nil {
+		return fmt.Errorf("unable to parse master file of zone %s: %s", hostname, err)
+	}
+
+	declared := make(map[string]bool)
+	if recordsets, err := parseZoneFile(hostname, masterfile); err == nil {
+		for _, rs := range recordsets {
+			declared[recordsetKey(rs.Name, rs.Type)] = true
+		}
+	}
+
+	managed := make([]dnsv2.Recordset, 0, len(liverecordsets))
+	for _, rs := range liverecordsets {
+		if isZoneApexRecordset(hostname, rs) && !declared[recordsetKey(rs.Name, rs.Type)] {
+			continue
+		}
+		managed = append(managed, rs)
+	}
+
+	records := zoneFileRecords(managed)
+	if !equivalentZoneFiles(hostname, masterfile, records) {
+		log.Printf("[DEBUG] [Akamai DNSv2] Master file of zone [%s] has changed", hostname)
+		d.Set("master_file", live)
+	}
+	return d.Set("master_file_records", records)
+}
+
+// suppressEquivalentZoneFiles suppresses master_file diffs that do not change
+// any record.
+func suppressEquivalentZoneFiles(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" || new == "" {
+		return false
+	}
+	recordsets, err := parseZoneFile(d.Get("zone").(string), old)
+	if err != nil {
+		return false
+	}
+	return equivalentZoneFiles(d.Get("zone").(string), new, zoneFileRecords(recordsets))
+}
+
+func equivalentZoneFiles(zone string, contents string, records []string) bool {
+	recordsets, err := parseZoneFile(zone, contents)
+	if err != nil {
+		return false
+	}
+	return strings.Join(zoneFileRecords(recordsets), "\n") == strings.Join(records, "\n")
+}
+
+// validateTSIGSecret is a SchemaValidateFunc to validate a base64 encoded TSIG secret.
+func validateTSIGSecret(v interface{}, k string) (ws []string, es []error) {
+	if _, err := base64.StdEncoding.DecodeString(v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%s must be base64 encoded", k))
+	}
+	return
+}
+
 // validateZoneType is a SchemaValidateFunc to validate the Zone type.
 func validateZoneType(v interface{}, k string) (ws []string, es []error) {
 	value := strings.ToUpper(v.(string))
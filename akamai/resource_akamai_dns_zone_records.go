@@ -0,0 +1,328 @@
+package akamai
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceDNSv2ZoneRecords() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDNSv2ZoneRecordsCreate,
+		Read:   resourceDNSv2ZoneRecordsRead,
+		Update: resourceDNSv2ZoneRecordsUpdate,
+		Delete: resourceDNSv2ZoneRecordsDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceDNSv2ZoneRecordsImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"zone": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"recordset": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Set:      hashZoneRecordset,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressEquivalentRecordsetName,
+						},
+						"type": {
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressEquivalentRecordsetType,
+						},
+						"ttl": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"rdata": {
+							Type:             schema.TypeList,
+							Required:         true,
+							MinItems:         1,
+							Elem:             &schema.Schema{Type: schema.TypeString},
+							DiffSuppressFunc: suppressEquivalentRecordsetRdata,
+						},
+					},
+				},
+			},
+			"version_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"managed_recordsets": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceDNSv2ZoneRecordsCreate(d *schema.ResourceData, meta interface{}) error {
+	zone := d.Get("zone").(string)
+
+	recordsets := expandZoneRecordsets(d.Get("recordset").(*schema.Set))
+	if err := applyZoneRecordsets(zone, recordsets); err != nil {
+		return fmt.Errorf("unable to update records of zone %s: %s", zone, err)
+	}
+
+	d.SetId(zone)
+	d.Set("managed_recordsets", zoneRecordsetKeys(recordsets))
+	return resourceDNSv2ZoneRecordsRead(d, meta)
+}
+
+func resourceDNSv2ZoneRecordsRead(d *schema.ResourceData, meta interface{}) error {
+	zone := d.Get("zone").(string)
+
+	log.Printf("[INFO] [Akamai DNSv2] READ Searching for zone records %s", zone)
+	live, err := getZoneRecordsets(zone)
+	if err != nil {
+		if isNotFoundError(err) {
+			log.Printf("[WARN] [Akamai DNSv2] Zone %s not found, removing from state", zone)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	// Recordsets that still match the configuration are stored as written
+	// there, so other spellings of the same names and rdata do not diff.
+	managed := make(map[string]map[string]interface{})
+	for _, v := range d.Get("recordset").(*schema.Set).List() {
+		m := v.(map[string]interface{})
+		rs := zoneRecordsetFromMap(m)
+		managed[recordsetKey(rs.Name, rs.Type)] = m
+	}
+
+	recordsets := make([]interface{}, 0, len(live))
+	for _, rs := range live {
+		key := recordsetKey(rs.Name, rs.Type)
+		m, ok := managed[key]
+		if isZoneApexRecordset(zone, rs) && !ok {
+			continue
+		}
+		rs = normalizeRecordset(rs)
+		if ok && reflect.DeepEqual(zoneRecordsetFromMap(m), rs) {
+			recordsets = append(recordsets, m)
+			continue
+		}
+		recordsets = append(recordsets, map[string]interface{}{
+			"name":  rs.Name,
+			"type":  rs.Type,
+			"ttl":   rs.TTL,
+			"rdata": rs.Rdata,
+		})
+	}
+	if err := d.Set("recordset", recordsets); err != nil {
+		return err
+	}
+	// Imported resources manage the recordsets that were imported until they
+	// are applied
+	if len(d.Get("managed_recordsets").([]interface{})) == 0 {
+		d.Set("managed_recordsets", zoneRecordsetKeys(expandZoneRecordsets(d.Get("recordset").(*schema.Set))))
+	}
+
+	zoneresponse, err := dnsv2.GetZone(zone)
+	if err != nil {
+		return err
+	}
+	d.Set("version_id", zoneresponse.VersionId)
+
+	return nil
+}
+
+func resourceDNSv2ZoneRecordsUpdate(d *schema.ResourceData, meta interface{}) error {
+	zone := d.Get("zone").(string)
+
+	recordsets := expandZoneRecordsets(d.Get("recordset").(*schema.Set))
+	if d.HasChange("recordset") {
+		if err := applyZoneRecordsets(zone, recordsets); err != nil {
+			return fmt.Errorf("unable to update records of zone %s: %s", zone, err)
+		}
+	}
+	d.Set("managed_recordsets", zoneRecordsetKeys(recordsets))
+
+	return resourceDNSv2ZoneRecordsRead(d, meta)
+}
+
+// resourceDNSv2ZoneRecordsDelete removes the recordsets last applied from the
+// configuration. Recordsets added to the zone outside of Terraform, and the
+// SOA and apex NS recordsets, are kept.
+func resourceDNSv2ZoneRecordsDelete(d *schema.ResourceData, meta interface{}) error {
+	zone := d.Get("zone").(string)
+
+	keys := d.Get("managed_recordsets").([]interface{})
+	managed := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		managed[k.(string)] = true
+	}
+
+	err := withZoneLock(zone, func() error {
+		live, err := getZoneRecordsets(zone)
+		if err != nil {
+			return err
+		}
+
+		kept := make([]dnsv2.Recordset, 0, len(live))
+		for _, rs := range live {
+			if !managed[recordsetKey(rs.Name, rs.Type)] || isZoneApexRecordset(zone, rs) {
+				kept = append(kept, rs)
+			}
+		}
+		return applyZoneRecordsetsLocked(zone, kept)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to delete records of zone %s: %s", zone, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceDNSv2ZoneRecordsImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	zone := d.Id()
+
+	if _, err := dnsv2.GetZone(zone); err != nil {
+		return nil, err
+	}
+
+	d.Set("zone", zone)
+	return []*schema.ResourceData{d}, nil
+}
+
+// applyZoneRecordsets makes desired the complete set of recordsets in zone,
+// with a single changelist. The SOA and apex NS recordsets are kept unless
+// they are part of desired.
+func applyZoneRecordsets(zone string, desired []dnsv2.Recordset) error {
+	return withZoneLock(zone, func() error {
+		return applyZoneRecordsetsLocked(zone, desired)
+	})
+}
+
+func applyZoneRecordsetsLocked(zone string, desired []dnsv2.Recordset) error {
+	live, err := getZoneRecordsets(zone)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, rs := range desired {
+		wanted[recordsetKey(rs.Name, rs.Type)] = true
+	}
+
+	full := make([]dnsv2.Recordset, 0, len(desired)+2)
+	full = append(full, desired...)
+	for _, rs := range live {
+		if isZoneApexRecordset(zone, rs) && !wanted[recordsetKey(rs.Name, rs.Type)] {
+			full = append(full, rs)
+		}
+	}
+
+	add, edit, remove := diffRecordsets(live, full)
+	log.Printf("[DEBUG] [Akamai DNSv2] Zone %s changes: %d to add, %d to change, %d to remove", zone, len(add), len(edit), len(remove))
+	if len(add) == 0 && len(edit) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	return submitZoneRecordsets(zone, full)
+}
+
+// zoneRecordsetKeys returns the recordset keys of recordsets.
+func zoneRecordsetKeys(recordsets []dnsv2.Recordset) []interface{} {
+	keys := make([]interface{}, 0, len(recordsets))
+	for _, rs := range recordsets {
+		keys = append(keys, recordsetKey(rs.Name, rs.Type))
+	}
+	return keys
+}
+
+func expandZoneRecordsets(set *schema.Set) []dnsv2.Recordset {
+	recordsets := make([]dnsv2.Recordset, 0, set.Len())
+	for _, v := range set.List() {
+		recordsets = append(recordsets, zoneRecordsetFromMap(v.(map[string]interface{})))
+	}
+	return recordsets
+}
+
+func zoneRecordsetFromMap(m map[string]interface{}) dnsv2.Recordset {
+	rdataList, _ := m["rdata"].([]interface{})
+	rdata := make([]string, 0, len(rdataList))
+	for _, r := range rdataList {
+		if s, ok := r.(string); ok {
+			rdata = append(rdata, s)
+		}
+	}
+
+	name, _ := m["name"].(string)
+	recordtype, _ := m["type"].(string)
+	ttl, _ := m["ttl"].(int)
+
+	return normalizeRecordset(dnsv2.Recordset{Name: name, Type: recordtype, TTL: ttl, Rdata: rdata})
+}
+
+// suppressEquivalentRecordsetName suppresses differences in case and in the
+// trailing dot of recordset names.
+func suppressEquivalentRecordsetName(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(strings.TrimSuffix(old, "."), strings.TrimSuffix(new, "."))
+}
+
+// suppressEquivalentRecordsetType suppresses differences in the case of
+// recordset types.
+func suppressEquivalentRecordsetType(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+// suppressEquivalentRecordsetRdata suppresses rdata changes that only differ in
+// order or form, by comparing the whole rdata list of the recordset in
+// canonical form.
+func suppressEquivalentRecordsetRdata(k, old, new string, d *schema.ResourceData) bool {
+	prefix := k[:strings.LastIndex(k, ".rdata")]
+	o, n := d.GetChange(prefix + ".rdata")
+	oldRdata, ok := o.([]interface{})
+	if !ok {
+		return false
+	}
+	newRdata, ok := n.([]interface{})
+	if !ok {
+		return false
+	}
+
+	recordtype := strings.ToUpper(d.Get(prefix + ".type").(string))
+	canonical := func(rdata []interface{}) string {
+		values := make([]string, 0, len(rdata))
+		for _, r := range rdata {
+			if s, ok := r.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return strings.Join(normalizeRecordsetRdata(recordtype, values), "\n")
+	}
+	return canonical(oldRdata) == canonical(newRdata)
+}
+
+// hashZoneRecordset hashes the canonical form of a recordset, so equivalent
+// spellings of the same rdata do not show up as changes.
+func hashZoneRecordset(v interface{}) int {
+	rs := zoneRecordsetFromMap(v.(map[string]interface{}))
+
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("%s-", recordsetKey(rs.Name, rs.Type)))
+	buf.WriteString(fmt.Sprintf("%d-", rs.TTL))
+	buf.WriteString(strings.Join(rs.Rdata, "-"))
+
+	return hashcode.String(buf.String())
+}
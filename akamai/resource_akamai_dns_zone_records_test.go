@@ -0,0 +1,168 @@
+package akamai
+
+import (
+	"fmt"
+	"testing"
+
+	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+var testAccAkamaiDNSv2ZoneRecordsConfig = fmt.Sprintf(`
+provider "akamai" {
+  papi_section = "dns"
+  dns_section = "dns"
+}
+
+data "akamai_contract" "contract" {
+}
+
+data "akamai_group" "group" {
+}
+
+resource "akamai_dns_zone" "test_zone" {
+	contract = "${data.akamai_contract.contract.id}"
+	zone = "exampleterraform.io"
+	type = "primary"
+	comment =  "This is a test zone"
+	group     = "${data.akamai_group.group.id}"
+	sign_and_serve = false
+}
+
+resource "akamai_dns_zone_records" "records" {
+	zone = "${akamai_dns_zone.test_zone.zone}"
+
+	recordset {
+		name = "www.exampleterraform.io"
+		type = "A"
+		ttl = 300
+		rdata = ["10.0.0.2", "10.0.0.3"]
+	}
+
+	recordset {
+		name = "cdn.exampleterraform.io"
+		type = "CNAME"
+		ttl = 600
+		rdata = ["www.exampleterraform.io"]
+	}
+}
+`)
+
+func TestAccAkamaiDNSv2ZoneRecords_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAkamaiDNSv2ZoneRecordsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAkamaiDNSv2ZoneRecordsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("akamai_dns_zone_records.records", "recordset.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "akamai_dns_zone_records.records",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckAkamaiDNSv2ZoneRecordsDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "akamai_dns_zone_records" {
+			continue
+		}
+
+		recordsets, err := getZoneRecordsets(rs.Primary.ID)
+		if err != nil {
+			if isNotFoundError(err) {
+				continue
+			}
+			return err
+		}
+		for _, r := range recordsets {
+			if !isZoneApexRecordset(rs.Primary.ID, r) {
+				return fmt.Errorf("zone %s still has %s record %s", rs.Primary.ID, r.Type, r.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func TestDiffRecordsets(t *testing.T) {
+	live := []dnsv2.Recordset{
+		{Name: "example.com", Type: "SOA", TTL: 86400, Rdata: []string{"a1-2.akam.net. hostmaster.example.com. 1 3600 600 604800 300"}},
+		{Name: "www.example.com", Type: "A", TTL: 300, Rdata: []string{"10.0.0.3", "10.0.0.2"}},
+		{Name: "cdn.example.com", Type: "CNAME", TTL: 300, Rdata: []string{"www.example.com."}},
+		{Name: "old.example.com", Type: "A", TTL: 300, Rdata: []string{"10.0.0.9"}},
+	}
+	desired := []dnsv2.Recordset{
+		{Name: "example.com", Type: "SOA", TTL: 86400, Rdata: []string{"a1-2.akam.net. hostmaster.example.com. 1 3600 600 604800 300"}},
+		{Name: "WWW.example.com.", Type: "a", TTL: 300, Rdata: []string{"10.0.0.2", "10.0.0.3"}},
+		{Name: "cdn.example.com", Type: "CNAME", TTL: 600, Rdata: []string{"www.example.com"}},
+		{Name: "new.example.com", Type: "AAAA", TTL: 300, Rdata: []string{"2001:db8::1"}},
+	}
+
+	add, edit, remove := diffRecordsets(live, desired)
+	if len(add) != 1 || add[0].Name != "new.example.com" || add[0].Rdata[0] != "2001:0db8:0000:0000:0000:0000:0000:0001" {
+		t.Errorf("unexpected recordsets to add: %v", add)
+	}
+	if len(edit) != 1 || edit[0].Name != "cdn.example.com" || edit[0].TTL != 600 {
+		t.Errorf("unexpected recordsets to change: %v", edit)
+	}
+	if len(remove) != 1 || remove[0].Name != "old.example.com" {
+		t.Errorf("unexpected recordsets to remove: %v", remove)
+	}
+}
+
+func TestHashZoneRecordset(t *testing.T) {
+	a := map[string]interface{}{"name": "cdn.example.com", "type": "CNAME", "ttl": 300, "rdata": []interface{}{"www.example.com"}}
+	b := map[string]interface{}{"name": "cdn.example.com.", "type": "cname", "ttl": 300, "rdata": []interface{}{"www.example.com."}}
+	c := map[string]interface{}{"name": "cdn.example.com", "type": "CNAME", "ttl": 600, "rdata": []interface{}{"www.example.com"}}
+
+	if hashZoneRecordset(a) != hashZoneRecordset(b) {
+		t.Error("equivalent recordsets should hash the same")
+	}
+	if hashZoneRecordset(a) == hashZoneRecordset(c) {
+		t.Error("recordsets with different TTLs should not hash the same")
+	}
+}
+
+func TestZoneRecordsEquivalentSpelling(t *testing.T) {
+	r := resourceDNSv2ZoneRecords()
+	d := r.TestResourceData()
+	d.SetId("example.com")
+	d.Set("zone", "example.com")
+	d.Set("recordset", []interface{}{
+		map[string]interface{}{"name": "www.example.com", "type": "A", "ttl": 300, "rdata": []interface{}{"192.0.2.10", "192.0.2.11"}},
+	})
+	d.Set("managed_recordsets", []interface{}{"www.example.com/A"})
+
+	raw := map[string]interface{}{
+		"zone": "example.com",
+		"recordset": []interface{}{
+			map[string]interface{}{"name": "WWW.example.com.", "type": "a", "ttl": 300, "rdata": []interface{}{"192.0.2.11", "192.0.2.10"}},
+		},
+	}
+	diff, err := r.Diff(d.State(), &terraform.ResourceConfig{Raw: raw, Config: raw}, nil)
+	if err != nil {
+		t.Fatalf("Value %v is invalid: %v", raw, err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Errorf("Value %v should not diff: %v", raw, diff)
+	}
+
+	raw["recordset"] = []interface{}{
+		map[string]interface{}{"name": "www.example.com", "type": "A", "ttl": 300, "rdata": []interface{}{"192.0.2.12"}},
+	}
+	diff, err = r.Diff(d.State(), &terraform.ResourceConfig{Raw: raw, Config: raw}, nil)
+	if err != nil {
+		t.Fatalf("Value %v is invalid: %v", raw, err)
+	}
+	if diff == nil || diff.Empty() {
+		t.Errorf("Value %v should diff", raw)
+	}
+}
@@ -1,13 +1,19 @@
 package akamai
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
 	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -98,37 +104,16 @@ func testAccCheckAkamaiDNSZoneDestroy(s *terraform.State) error {
 			continue
 		}
 
-		hostname := strings.Split(rs.Primary.ID, "-")[5]
+		hostname := rs.Primary.Attributes["zone"]
 		zone, err := dnsv2.GetZone(hostname)
 		if err != nil {
+			if dnsv2.IsConfigDNSError(err) && err.(dnsv2.ConfigDNSError).NotFound() {
+				continue
+			}
 			return err
 		}
 		log.Printf("[DEBUG] [Akamai DNSv2] Searching for zone [%v]", zone)
-		/*
-			if len(zone.Zone.A) > 0 ||
-				len(zone.Zone.Aaaa) > 0 ||
-				len(zone.Zone.Afsdb) > 0 ||
-				len(zone.Zone.Cname) > 0 ||
-				len(zone.Zone.Dnskey) > 0 ||
-				len(zone.Zone.Ds) > 0 ||
-				len(zone.Zone.Hinfo) > 0 ||
-				len(zone.Zone.Loc) > 0 ||
-				len(zone.Zone.Mx) > 0 ||
-				len(zone.Zone.Naptr) > 0 ||
-				len(zone.Zone.Nsec3) > 0 ||
-				len(zone.Zone.Nsec3param) > 0 ||
-				len(zone.Zone.Ptr) > 0 ||
-				len(zone.Zone.Rp) > 0 ||
-				len(zone.Zone.Rrsig) > 0 ||
-				len(zone.Zone.Spf) > 0 ||
-				len(zone.Zone.Srv) > 0 ||
-				len(zone.Zone.Sshfp) > 0 ||
-				len(zone.Zone.Txt) > 0 {
-				// These never get deleted
-				// len(zone.Zone.Ns) > 0 ||
-				// len(zone.Zone.Soa) > 0 ||
-				return fmt.Errorf("zone was not deleted %s", hostname)
-			}*/
+		return fmt.Errorf("zone was not deleted %s", hostname)
 	}
 	return nil
 }
@@ -166,3 +151,101 @@ func TestValidateZoneType(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateTSIGSecret(t *testing.T) {
+	badValues := []string{"not base64!", "abc"}
+	goodValues := []string{"dGVzdHNlY3JldA==", "YWJjZA=="}
+
+	for _, bv := range badValues {
+		_, err := validateTSIGSecret(bv, "secret")
+		if err == nil {
+			t.Errorf("Value %v is invalid: %v", bv, err)
+		}
+	}
+
+	for _, gv := range goodValues {
+		_, err := validateTSIGSecret(gv, "secret")
+		if err != nil {
+			t.Errorf("Value %v is invalid: %v", gv, err)
+		}
+	}
+}
+
+func TestDNSZoneStringRedactsSecret(t *testing.T) {
+	zone := &dnsZone{
+		Zone:    "exampleterraform.io",
+		Type:    "SECONDARY",
+		Masters: []string{"1.2.3.4"},
+		TsigKey: &dnsTSIGKey{Name: "transfer", Algorithm: "hmac-sha256", Secret: "dGVzdHNlY3JldA=="},
+	}
+
+	logged := fmt.Sprintf("%v", zone)
+	if strings.Contains(logged, "dGVzdHNlY3JldA==") || !strings.Contains(logged, "transfer") {
+		t.Errorf("Value %v is invalid", logged)
+	}
+	if zone.TsigKey.Secret != "dGVzdHNlY3JldA==" {
+		t.Errorf("Value %v is invalid: secret was modified", zone.TsigKey.Secret)
+	}
+}
+
+func TestIsZoneConversion(t *testing.T) {
+	conversions := [][2]string{{"SECONDARY", "PRIMARY"}, {"SECONDARY", "primary"}}
+	others := [][2]string{{"PRIMARY", "SECONDARY"}, {"ALIAS", "PRIMARY"}, {"PRIMARY", "PRIMARY"}, {"", "PRIMARY"}}
+
+	for _, c := range conversions {
+		if !isZoneConversion(c[0], c[1]) {
+			t.Errorf("Value %v is invalid: should be a conversion", c)
+		}
+	}
+	for _, c := range others {
+		if isZoneConversion(c[0], c[1]) {
+			t.Errorf("Value %v is invalid: should not be a conversion", c)
+		}
+	}
+}
+
+func TestConvertSecondaryZoneRollback(t *testing.T) {
+	var types []string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/zone-file"):
+			w.Header().Set("Content-Type", "text/dns")
+			fmt.Fprint(w, "www.example.com. 300 IN A 192.0.2.10\n")
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/zone-file"):
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.Method == "PUT":
+			var zone dnsZone
+			json.NewDecoder(r.Body).Decode(&zone)
+			types = append(types, zone.Type+" "+strings.Join(zone.Masters, ","))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	httpClient, config := client.Client, dnsv2.Config
+	client.Client = srv.Client()
+	dnsv2.Config = edgegrid.Config{Host: srv.URL, ClientToken: "test", ClientSecret: "test", AccessToken: "test", MaxBody: 131072}
+	defer func() { client.Client, dnsv2.Config = httpClient, config }()
+
+	d := resourceDNSv2Zone().TestResourceData()
+	d.SetId("example.com")
+	d.Set("zone", "example.com")
+	d.Set("type", "SECONDARY")
+	d.Set("masters", []interface{}{"192.0.2.1"})
+	state := d.State()
+	diff := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{
+		"type":      {Old: "SECONDARY", New: "PRIMARY"},
+		"masters.#": {Old: "1", New: "0"},
+	}}
+	d, err := schema.InternalMap(resourceDNSv2Zone().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("Value %v is invalid: %v", diff, err)
+	}
+
+	if err := convertSecondaryZone(d, expandDNSZone(d)); err == nil {
+		t.Fatalf("Value %v should fail when the records can not be uploaded", d.Get("zone"))
+	}
+	if len(types) != 2 || types[0] != "PRIMARY " || types[1] != "SECONDARY 192.0.2.1" {
+		t.Errorf("Value %v is invalid: the zone should be converted back to SECONDARY", types)
+	}
+}
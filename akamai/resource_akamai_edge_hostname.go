@@ -3,19 +3,24 @@ package akamai
 import (
 	"errors"
 	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	cps "github.com/akamai/AkamaiOPEN-edgegrid-golang/cps-v2"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
 	"github.com/hashicorp/terraform/helper/schema"
-	"log"
-	"strings"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
 func resourceSecureEdgeHostName() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceSecureEdgeHostNameCreate,
-		Read:   resourceSecureEdgeHostNameRead,
-		Delete: resourceSecureEdgeHostNameDelete,
-		Exists: resourceSecureEdgeHostNameExists,
+		Create:        resourceSecureEdgeHostNameCreate,
+		Read:          resourceSecureEdgeHostNameRead,
+		Delete:        resourceSecureEdgeHostNameDelete,
+		Exists:        resourceSecureEdgeHostNameExists,
+		CustomizeDiff: resourceSecureEdgeHostNameCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: resourceSecureEdgeHostNameImport,
 		},
@@ -60,11 +65,162 @@ var akamaiSecureEdgeHostNameSchema = map[string]*schema.Schema{
 		Type:     schema.TypeString,
 		Computed: true,
 	},
+	"secure_network": {
+		Type:             schema.TypeString,
+		Optional:         true,
+		ForceNew:         true,
+		DiffSuppressFunc: suppressDefaultSecureNetwork,
+		ValidateFunc: validation.StringInSlice([]string{
+			secureNetworkStandardTLS,
+			secureNetworkEnhancedTLS,
+			secureNetworkSharedCert,
+		}, false),
+	},
 	"certificate": {
 		Type:     schema.TypeInt,
 		Optional: true,
 		ForceNew: true,
 	},
+	"china_cdn": {
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+		ForceNew: true,
+	},
+	"custom_china_cdn_map": {
+		Type:     schema.TypeString,
+		Optional: true,
+		ForceNew: true,
+	},
+}
+
+const (
+	secureNetworkStandardTLS = "STANDARD_TLS"
+	secureNetworkEnhancedTLS = "ENHANCED_TLS"
+	secureNetworkSharedCert  = "SHARED_CERT"
+)
+
+// edgeHostnameSuffixes lists the supported edge hostname domain suffixes and
+// the secure network each one is provisioned on unless overridden.
+var edgeHostnameSuffixes = []struct {
+	suffix        string
+	secureNetwork string
+}{
+	{"edgesuite.net", secureNetworkStandardTLS},
+	{"edgesuite-staging.net", secureNetworkStandardTLS},
+	{"edgekey.net", secureNetworkEnhancedTLS},
+	{"edgekey-staging.net", secureNetworkEnhancedTLS},
+	{"akamaized.net", secureNetworkSharedCert},
+	{"akamaized-staging.net", secureNetworkSharedCert},
+}
+
+// parseEdgeHostname splits an edge hostname into its prefix and domain suffix and
+// returns the default secure network for that suffix.
+func parseEdgeHostname(edgeHostname string) (prefix, suffix, secureNetwork string, err error) {
+	for _, s := range edgeHostnameSuffixes {
+		if strings.HasSuffix(edgeHostname, "."+s.suffix) {
+			prefix = strings.TrimSuffix(edgeHostname, "."+s.suffix)
+			return prefix, s.suffix, s.secureNetwork, nil
+		}
+	}
+
+	suffixes := make([]string, len(edgeHostnameSuffixes))
+	for i, s := range edgeHostnameSuffixes {
+		suffixes[i] = s.suffix
+	}
+	return "", "", "", fmt.Errorf("edge hostname %s must end with one of: %s", edgeHostname, strings.Join(suffixes, ", "))
+}
+
+// suppressDefaultSecureNetwork hides the secure network read back from the
+// API when it is not configured and matches the network of the domain suffix.
+// The attribute is not computed, so an unset value stays known at plan time and
+// the certificate checks of resourceSecureEdgeHostNameCustomizeDiff can run.
+func suppressDefaultSecureNetwork(k, old, new string, d *schema.ResourceData) bool {
+	if new != "" {
+		return false
+	}
+	_, _, secureNetwork, err := parseEdgeHostname(d.Get("edge_hostname").(string))
+	return err == nil && old == secureNetwork
+}
+
+// certificateCoversHostname reports whether hostname matches the common name or
+// one of the SANs of a CPS enrollment, honoring single-label wildcards.
+func certificateCoversHostname(enrollment *cps.Enrollment, hostname string) bool {
+	if enrollment.CertificateSigningRequest == nil {
+		return false
+	}
+
+	names := []string{enrollment.CertificateSigningRequest.CommonName}
+	if enrollment.CertificateSigningRequest.AlternativeNames != nil {
+		names = append(names, *enrollment.CertificateSigningRequest.AlternativeNames...)
+	}
+
+	hostname = strings.ToLower(hostname)
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if name == hostname {
+			return true
+		}
+		if strings.HasPrefix(name, "*.") {
+			if i := strings.Index(hostname, "."); i > 0 && hostname[i:] == name[1:] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func resourceSecureEdgeHostNameCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("edge_hostname") && !d.HasChange("secure_network") && !d.HasChange("certificate") && !d.HasChange("custom_china_cdn_map") {
+		return nil
+	}
+
+	if d.NewValueKnown("china_cdn") && d.NewValueKnown("custom_china_cdn_map") && !d.Get("china_cdn").(bool) && d.Get("custom_china_cdn_map").(string) != "" {
+		return errors.New("custom_china_cdn_map can only be set on China CDN edge hostnames")
+	}
+
+	// secure_network is only unknown when it is configured from a value that
+	// is not known yet. When it is not configured, the suffix decides below.
+	if !d.NewValueKnown("edge_hostname") || !d.NewValueKnown("secure_network") {
+		return nil
+	}
+
+	edgeHostname := d.Get("edge_hostname").(string)
+	prefix, _, secureNetwork, err := parseEdgeHostname(edgeHostname)
+	if err != nil {
+		return err
+	}
+
+	if v, ok := d.GetOk("secure_network"); ok {
+		secureNetwork = v.(string)
+	}
+
+	if secureNetwork != secureNetworkEnhancedTLS || !d.NewValueKnown("certificate") {
+		return nil
+	}
+
+	certEnrollmentID, ok := d.GetOk("certificate")
+	if !ok {
+		return fmt.Errorf("a certificate enrollment ID is required for Enhanced TLS edge hostname %s", edgeHostname)
+	}
+
+	if config, ok := meta.(*Config); !ok || !config.cps {
+		log.Printf("[WARN] CPS credentials are not configured, certificate enrollment %d is not checked against %s", certEnrollmentID.(int), prefix)
+		return nil
+	}
+
+	log.Printf("[DEBUG] Fetching certificate enrollment %d", certEnrollmentID.(int))
+	enrollment, err := cps.GetEnrollment(fmt.Sprintf("/cps/v2/enrollments/%d", certEnrollmentID.(int)))
+	if err != nil {
+		return fmt.Errorf("unable to fetch certificate enrollment %d with the CPS credentials (cps or cps_section): %s", certEnrollmentID.(int), err)
+	}
+
+	if !certificateCoversHostname(enrollment, prefix) {
+		return fmt.Errorf("certificate enrollment %d does not cover %s in its common name or SANs", certEnrollmentID.(int), prefix)
+	}
+
+	return nil
 }
 
 func resourceSecureEdgeHostNameCreate(d *schema.ResourceData, meta interface{}) error {
@@ -109,20 +265,15 @@ func resourceSecureEdgeHostNameCreate(d *schema.ResourceData, meta interface{})
 	ehn.ProductID = product.ProductID
 	ehn.EdgeHostnameDomain = edgeHostname
 
-	switch {
-	case strings.HasSuffix(edgeHostname, ".edgesuite.net"):
-		ehn.DomainPrefix = strings.TrimSuffix(edgeHostname, ".edgesuite.net")
-		ehn.DomainSuffix = "edgesuite.net"
-		ehn.SecureNetwork = "STANDARD_TLS"
-	case strings.HasSuffix(edgeHostname, ".edgekey.net"):
-		ehn.DomainPrefix = strings.TrimSuffix(edgeHostname, ".edgekey.net")
-		ehn.DomainSuffix = "edgekey.net"
-		ehn.SecureNetwork = "ENHANCED_TLS"
-	case strings.HasSuffix(edgeHostname, ".akamaized.net"):
-		ehn.DomainPrefix = strings.TrimSuffix(edgeHostname, ".akamaized.net")
-		ehn.DomainSuffix = "akamaized.net"
-		ehn.SecureNetwork = "SHARED_CERT"
+	ehn.DomainPrefix, ehn.DomainSuffix, ehn.SecureNetwork, err = parseEdgeHostname(edgeHostname)
+	if err != nil {
+		return err
+	}
+
+	if secureNetwork, ok := d.GetOk("secure_network"); ok {
+		ehn.SecureNetwork = secureNetwork.(string)
 	}
+	d.Set("secure_network", ehn.SecureNetwork)
 
 	ipv4 := d.Get("ipv4").(bool)
 	if ipv4 {
@@ -143,8 +294,8 @@ func resourceSecureEdgeHostNameCreate(d *schema.ResourceData, meta interface{})
 	if certEnrollmentId, ok := d.GetOk("certificate"); ok {
 		ehn.CertEnrollmentId = certEnrollmentId.(int)
 		ehn.SlotNumber = certEnrollmentId.(int)
-	} else if ehn.SecureNetwork == "ENHANCED_TLS" {
-		return errors.New("A certificate enrollment ID is required for Enhanced TLS (edgekey.net) edge hostnames")
+	} else if ehn.SecureNetwork == secureNetworkEnhancedTLS {
+		return errors.New("A certificate enrollment ID is required for Enhanced TLS edge hostnames")
 	}
 
 	if ehnFound, err := edgeHostnames.FindEdgeHostname(ehn); ehnFound != nil && ehnFound.EdgeHostnameID != "" {
@@ -160,6 +311,13 @@ func resourceSecureEdgeHostNameCreate(d *schema.ResourceData, meta interface{})
 
 		log.Println("[DEBUG] Existing edge hostname FOUND = ", ehnFound.EdgeHostnameID)
 		d.SetId(ehnFound.EdgeHostnameID)
+	} else if d.Get("china_cdn").(bool) {
+		log.Printf("[DEBUG] Creating new China CDN edge hostname: %#v\n\n", ehn)
+		edgeHostnameID, err := createChinaCDNEdgeHostname(ehn, contract.ContractID, group.GroupID, d.Get("custom_china_cdn_map").(string))
+		if err != nil {
+			return err
+		}
+		d.SetId(edgeHostnameID)
 	} else {
 		log.Printf("[DEBUG] Creating new edge hostname: %#v\n\n", ehn)
 		err = ehn.Save("")
@@ -175,6 +333,39 @@ func resourceSecureEdgeHostNameCreate(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
+// chinaCDNEdgeHostname is an edge hostname mapped to China CDN. papi-v1 has no
+// chinaCdn member, so these are created with papiDo.
+type chinaCDNEdgeHostname struct {
+	*papi.EdgeHostname
+	ChinaCdn struct {
+		IsChinaCdn        bool   `json:"isChinaCdn"`
+		CustomChinaCdnMap string `json:"customChinaCdnMap,omitempty"`
+	} `json:"chinaCdn"`
+}
+
+// createChinaCDNEdgeHostname creates an edge hostname mapped to China CDN and
+// returns its ID.
+func createChinaCDNEdgeHostname(ehn *papi.EdgeHostname, contractID string, groupID string, customMap string) (string, error) {
+	body := chinaCDNEdgeHostname{EdgeHostname: ehn}
+	body.ChinaCdn.IsChinaCdn = true
+	body.ChinaCdn.CustomChinaCdnMap = customMap
+
+	var location struct {
+		EdgeHostnameLink string `json:"edgeHostnameLink"`
+	}
+	path := fmt.Sprintf("/papi/v1/edgehostnames?contractId=%s&groupId=%s", url.QueryEscape(contractID), url.QueryEscape(groupID))
+	if err := papiDo("POST", path, body, &location); err != nil {
+		return "", err
+	}
+
+	for _, part := range strings.Split(strings.SplitN(location.EdgeHostnameLink, "?", 2)[0], "/") {
+		if strings.HasPrefix(part, "ehn_") {
+			return part, nil
+		}
+	}
+	return "", fmt.Errorf("unexpected edge hostname link %q", location.EdgeHostnameLink)
+}
+
 func resourceSecureEdgeHostNameDelete(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] DELETING")
 
@@ -292,6 +483,9 @@ func resourceSecureEdgeHostNameRead(d *schema.ResourceData, meta interface{}) er
 				foundEdgeHostname = true
 				defaultEdgeHostname = eHn
 				edgeHostnameID = eHn.EdgeHostnameID
+				if eHn.SecureNetwork != "" {
+					d.Set("secure_network", eHn.SecureNetwork)
+				}
 			}
 		}
 		log.Println("[DEBUG] Found EdgeHostname ", foundEdgeHostname)
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"log"
 
+	cps "github.com/akamai/AkamaiOPEN-edgegrid-golang/cps-v2"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+	"github.com/hashicorp/terraform/config/hcl2shim"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 	//"strings"
@@ -121,3 +123,112 @@ func testAccCheckAkamaiSecureEdgeHostNameExists(s *terraform.State) error {
 	}
 	return nil
 }
+
+func TestParseEdgeHostname(t *testing.T) {
+	tests := []struct {
+		edgeHostname  string
+		prefix        string
+		suffix        string
+		secureNetwork string
+	}{
+		{"www.example.com.edgesuite.net", "www.example.com", "edgesuite.net", "STANDARD_TLS"},
+		{"www.example.com.edgekey.net", "www.example.com", "edgekey.net", "ENHANCED_TLS"},
+		{"www.example.com.edgekey-staging.net", "www.example.com", "edgekey-staging.net", "ENHANCED_TLS"},
+		{"www.example.com.akamaized.net", "www.example.com", "akamaized.net", "SHARED_CERT"},
+	}
+
+	for _, tt := range tests {
+		prefix, suffix, secureNetwork, err := parseEdgeHostname(tt.edgeHostname)
+		if err != nil {
+			t.Errorf("Value %v is invalid: %v", tt.edgeHostname, err)
+			continue
+		}
+		if prefix != tt.prefix || suffix != tt.suffix || secureNetwork != tt.secureNetwork {
+			t.Errorf("parseEdgeHostname(%v) = %v, %v, %v", tt.edgeHostname, prefix, suffix, secureNetwork)
+		}
+	}
+
+	if _, _, _, err := parseEdgeHostname("www.example.com.example.net"); err == nil {
+		t.Errorf("Value www.example.com.example.net should be invalid")
+	}
+}
+
+func TestCertificateCoversHostname(t *testing.T) {
+	sans := []string{"*.example.com", "example.org"}
+	enrollment := &cps.Enrollment{
+		CertificateSigningRequest: &cps.CSR{
+			CommonName:       "www.example.net",
+			AlternativeNames: &sans,
+		},
+	}
+
+	for _, hostname := range []string{"www.example.net", "www.example.com", "example.org"} {
+		if !certificateCoversHostname(enrollment, hostname) {
+			t.Errorf("Hostname %v should be covered", hostname)
+		}
+	}
+
+	for _, hostname := range []string{"example.com", "a.www.example.com", "www.example.org"} {
+		if certificateCoversHostname(enrollment, hostname) {
+			t.Errorf("Hostname %v should not be covered", hostname)
+		}
+	}
+}
+
+func TestSecureEdgeHostNameCustomizeDiff(t *testing.T) {
+	config := func(raw map[string]interface{}) *terraform.ResourceConfig {
+		raw["product"] = "prd_SPM"
+		raw["contract"] = "ctr_1-ABC"
+		raw["group"] = "grp_12345"
+		c := &terraform.ResourceConfig{Raw: raw, Config: raw}
+		for k, v := range raw {
+			if v == hcl2shim.UnknownVariableValue {
+				c.ComputedKeys = append(c.ComputedKeys, k)
+			}
+		}
+		return c
+	}
+
+	_, err := resourceSecureEdgeHostName().Diff(nil, config(map[string]interface{}{
+		"edge_hostname": "www.example.com.edgekey.net",
+	}), nil)
+	if err == nil {
+		t.Errorf("Value %v should require a certificate", "www.example.com.edgekey.net")
+	}
+
+	_, err = resourceSecureEdgeHostName().Diff(nil, config(map[string]interface{}{
+		"edge_hostname":        "www.example.com.edgesuite.net",
+		"custom_china_cdn_map": "example.cn.akamai.net",
+	}), nil)
+	if err == nil {
+		t.Errorf("Value %v should require china_cdn", "custom_china_cdn_map")
+	}
+
+	// Without CPS credentials, the enrollment is not fetched.
+	for _, raw := range []map[string]interface{}{
+		{"edge_hostname": "www.example.com.edgekey.net", "secure_network": "STANDARD_TLS"},
+		{"edge_hostname": "www.example.com.edgesuite.net"},
+		{"edge_hostname": "www.example.com.edgekey.net", "secure_network": hcl2shim.UnknownVariableValue},
+		{"edge_hostname": "www.example.com.edgekey.net", "certificate": 12345},
+		{"edge_hostname": "www.example.com.edgesuite.net", "china_cdn": true, "custom_china_cdn_map": "example.cn.akamai.net"},
+	} {
+		if _, err := resourceSecureEdgeHostName().Diff(nil, config(raw), &Config{}); err != nil {
+			t.Errorf("Value %v is invalid: %v", raw, err)
+		}
+	}
+}
+
+func TestSuppressDefaultSecureNetwork(t *testing.T) {
+	d := resourceSecureEdgeHostName().TestResourceData()
+	d.Set("edge_hostname", "www.example.com.edgekey.net")
+
+	if !suppressDefaultSecureNetwork("secure_network", "ENHANCED_TLS", "", d) {
+		t.Errorf("Value %v should be suppressed", "ENHANCED_TLS")
+	}
+	if suppressDefaultSecureNetwork("secure_network", "SHARED_CERT", "", d) {
+		t.Errorf("Value %v should not be suppressed", "SHARED_CERT")
+	}
+	if suppressDefaultSecureNetwork("secure_network", "ENHANCED_TLS", "STANDARD_TLS", d) {
+		t.Errorf("Value %v should not be suppressed", "STANDARD_TLS")
+	}
+}
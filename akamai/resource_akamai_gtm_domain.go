@@ -23,7 +23,7 @@ func resourceGTMv1Domain() *schema.Resource {
 		Delete: resourceGTMv1DomainDelete,
 		Exists: resourceGTMv1DomainExists,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceGTMv1DomainImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"contract": {
@@ -57,15 +57,18 @@ func resourceGTMv1Domain() *schema.Resource {
 			},
 			"default_unreachable_threshold": {
 				Type:     schema.TypeFloat,
+				Optional: true,
 				Computed: true,
 			},
 			"email_notification_list": {
 				Type:     schema.TypeList,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Optional: true,
+				Computed: true,
 			},
 			"min_pingable_region_fraction": {
 				Type:     schema.TypeFloat,
+				Optional: true,
 				Computed: true,
 			},
 			"default_timeout_penalty": {
@@ -75,47 +78,58 @@ func resourceGTMv1Domain() *schema.Resource {
 			},
 			"servermonitor_liveness_count": {
 				Type:     schema.TypeInt,
+				Optional: true,
 				Computed: true,
 			},
 			"round_robin_prefix": {
 				Type:     schema.TypeString,
+				Optional: true,
 				Computed: true,
 			},
 			"servermonitor_load_count": {
 				Type:     schema.TypeInt,
+				Optional: true,
 				Computed: true,
 			},
 			"ping_interval": {
 				Type:     schema.TypeInt,
+				Optional: true,
 				Computed: true,
 			},
 			"max_ttl": {
 				Type:     schema.TypeInt,
+				Optional: true,
 				Computed: true,
 			},
 			"load_imbalance_percentage": {
 				Type:     schema.TypeFloat,
 				Optional: true,
+				Computed: true,
 			},
 			"default_health_max": {
 				Type:     schema.TypeFloat,
+				Optional: true,
 				Computed: true,
 			},
 			"map_update_interval": {
 				Type:     schema.TypeInt,
+				Optional: true,
 				Computed: true,
 			},
 			"max_properties": {
 				Type:     schema.TypeInt,
+				Optional: true,
 				Computed: true,
 			},
 			"max_resources": {
 				Type:     schema.TypeInt,
+				Optional: true,
 				Computed: true,
 			},
 			"default_ssl_client_private_key": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
 			},
 			"default_error_penalty": {
 				Type:     schema.TypeInt,
@@ -124,51 +138,99 @@ func resourceGTMv1Domain() *schema.Resource {
 			},
 			"max_test_timeout": {
 				Type:     schema.TypeFloat,
+				Optional: true,
 				Computed: true,
 			},
 			"cname_coalescing_enabled": {
 				Type:     schema.TypeBool,
 				Optional: true,
+				Computed: true,
 			},
 			"default_health_multiplier": {
 				Type:     schema.TypeFloat,
+				Optional: true,
 				Computed: true,
 			},
 			"servermonitor_pool": {
 				Type:     schema.TypeString,
+				Optional: true,
 				Computed: true,
 			},
 			"load_feedback": {
 				Type:     schema.TypeBool,
 				Optional: true,
+				Computed: true,
 			},
 			"min_ttl": {
 				Type:     schema.TypeInt,
+				Optional: true,
 				Computed: true,
 			},
 			"default_max_unreachable_penalty": {
 				Type:     schema.TypeInt,
+				Optional: true,
 				Computed: true,
 			},
 			"default_health_threshold": {
 				Type:     schema.TypeFloat,
+				Optional: true,
 				Computed: true,
 			},
 			"min_test_interval": {
 				Type:     schema.TypeInt,
+				Optional: true,
 				Computed: true,
 			},
 			"ping_packet_size": {
 				Type:     schema.TypeInt,
+				Optional: true,
 				Computed: true,
 			},
 			"default_ssl_client_certificate": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
 			},
 			"end_user_mapping_enabled": {
 				Type:     schema.TypeBool,
 				Optional: true,
+				Computed: true,
+			},
+			"modification_comments": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"last_modified_by": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"change_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"passing_validation": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"propagation_status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"propagation_status_date": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
 			},
 		},
 	}
@@ -370,6 +432,77 @@ func resourceGTMv1DomainDelete(d *schema.ResourceData, meta interface{}) error {
 
 }
 
+// Import GTM Domain. An ID of the form domain:all also imports every datacenter,
+// property, resource and map of the domain.
+func resourceGTMv1DomainImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+
+	log.Printf("[INFO] [Akamai GTM] Domain [%s] Import", d.Id())
+	name := d.Id()
+	all := strings.HasSuffix(name, gtmImportAllSuffix)
+	name = strings.TrimSuffix(name, gtmImportAllSuffix)
+
+	dom, err := gtm.GetDomain(name)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(dom.Name)
+	d.Set("wait_on_complete", true)
+	populateTerraformState(d, dom)
+
+	results := []*schema.ResourceData{d}
+	if all {
+		results = append(results, importGTMDomainChildren(dom)...)
+	}
+	log.Printf("[INFO] [Akamai GTM] Domain [%s] Imported with %d resources", d.Id(), len(results))
+	return results, nil
+}
+
+const gtmImportAllSuffix = ":all"
+
+// gtmDefaultDatacenters are created with every domain. They are read with the
+// akamai_gtm_default_datacenter data source, not managed as datacenters.
+var gtmDefaultDatacenters = map[int]bool{5400: true, 5401: true, 5402: true}
+
+// importGTMDomainChildren returns the state of every datacenter, property,
+// resource and map of a domain, with the IDs their own importers use.
+func importGTMDomainChildren(dom *gtm.Domain) []*schema.ResourceData {
+
+	var results []*schema.ResourceData
+	child := func(resource *schema.Resource, resourceType string, id string) *schema.ResourceData {
+		c := resource.Data(nil)
+		c.SetType(resourceType)
+		c.SetId(id)
+		c.Set("domain", dom.Name)
+		c.Set("wait_on_complete", true)
+		results = append(results, c)
+		return c
+	}
+
+	for _, dc := range dom.Datacenters {
+		if gtmDefaultDatacenters[dc.DatacenterId] {
+			continue
+		}
+		populateTerraformDCState(child(resourceGTMv1Datacenter(), "akamai_gtm_datacenter", fmt.Sprintf("%s:%d", dom.Name, dc.DatacenterId)), dc)
+	}
+	for _, prop := range dom.Properties {
+		populateTerraformPropertyState(child(resourceGTMv1Property(), "akamai_gtm_property", fmt.Sprintf("%s:%s", dom.Name, prop.Name)), prop)
+	}
+	for _, rsrc := range dom.Resources {
+		populateTerraformResourceState(child(resourceGTMv1Resource(), "akamai_gtm_resource", fmt.Sprintf("%s:%s", dom.Name, rsrc.Name)), rsrc)
+	}
+	for _, as := range dom.AsMaps {
+		populateTerraformASmapState(child(resourceGTMv1ASmap(), "akamai_gtm_asmap", fmt.Sprintf("%s:%s", dom.Name, as.Name)), as)
+	}
+	for _, geo := range dom.GeographicMaps {
+		populateTerraformGeoMapState(child(resourceGTMv1Geomap(), "akamai_gtm_geomap", fmt.Sprintf("%s:%s", dom.Name, geo.Name)), geo)
+	}
+	for _, cidr := range dom.CidrMaps {
+		populateTerraformCidrMapState(child(resourceGTMv1Cidrmap(), "akamai_gtm_cidrmap", fmt.Sprintf("%s:%s", dom.Name, cidr.Name)), cidr)
+	}
+
+	return results
+}
+
 // Test GTM Domain existance
 func resourceGTMv1DomainExists(d *schema.ResourceData, meta interface{}) (bool, error) {
 
@@ -411,8 +544,8 @@ func populateDomainObject(d *schema.ResourceData, dom *gtm.Domain) {
 			dom.Type = v.(string)
 		}
 	}
-	if v, ok := d.GetOk("default_unreachable_threshold"); ok {
-		dom.DefaultUnreachableThreshold = v.(float32)
+	if v, ok := d.GetOkExists("default_unreachable_threshold"); ok {
+		dom.DefaultUnreachableThreshold = float32(v.(float64))
 	}
 	if v, ok := d.GetOk("email_notification_list"); ok {
 		ls := make([]string, len(v.([]interface{})))
@@ -421,54 +554,50 @@ func populateDomainObject(d *schema.ResourceData, dom *gtm.Domain) {
 		}
 		dom.EmailNotificationList = ls
 	}
-	if v, ok := d.GetOk("min_pingable_region_fraction"); ok {
-		dom.MinPingableRegionFraction = v.(float32)
+	if v, ok := d.GetOkExists("min_pingable_region_fraction"); ok {
+		dom.MinPingableRegionFraction = float32(v.(float64))
 	}
-	if v, ok := d.GetOk("default_timeout_penalty"); ok {
-		dom.DefaultTimeoutPenalty = v.(int)
-	}
-	if v, ok := d.GetOk("servermonitor_liveness_count"); ok {
+	dom.DefaultTimeoutPenalty = d.Get("default_timeout_penalty").(int)
+	if v, ok := d.GetOkExists("servermonitor_liveness_count"); ok {
 		dom.ServermonitorLivenessCount = v.(int)
 	}
 	if v, ok := d.GetOk("round_robin_prefix"); ok {
 		dom.RoundRobinPrefix = v.(string)
 	}
-	if v, ok := d.GetOk("servermonitor_load_count"); ok {
+	if v, ok := d.GetOkExists("servermonitor_load_count"); ok {
 		dom.ServermonitorLoadCount = v.(int)
 	}
-	if v, ok := d.GetOk("ping_interval"); ok {
+	if v, ok := d.GetOkExists("ping_interval"); ok {
 		dom.PingInterval = v.(int)
 	}
-	if v, ok := d.GetOk("max_ttl"); ok {
+	if v, ok := d.GetOkExists("max_ttl"); ok {
 		dom.MaxTTL = int64(v.(int))
 	}
-	if v, ok := d.GetOk("load_imbalance_percentage"); ok {
+	if v, ok := d.GetOkExists("load_imbalance_percentage"); ok {
 		dom.LoadImbalancePercentage = v.(float64)
 	}
-	if v, ok := d.GetOk("default_health_max"); ok {
+	if v, ok := d.GetOkExists("default_health_max"); ok {
 		dom.DefaultHealthMax = v.(float64)
 	}
-	if v, ok := d.GetOk("map_update_interval"); ok {
+	if v, ok := d.GetOkExists("map_update_interval"); ok {
 		dom.MapUpdateInterval = v.(int)
 	}
-	if v, ok := d.GetOk("max_properties"); ok {
+	if v, ok := d.GetOkExists("max_properties"); ok {
 		dom.MaxProperties = v.(int)
 	}
-	if v, ok := d.GetOk("max_resources"); ok {
+	if v, ok := d.GetOkExists("max_resources"); ok {
 		dom.MaxResources = v.(int)
 	}
 	if v, ok := d.GetOk("default_ssl_client_private_key"); ok {
 		dom.DefaultSslClientPrivateKey = v.(string)
 	}
-	if v, ok := d.GetOk("default_error_penalty"); ok {
-		dom.DefaultErrorPenalty = v.(int)
-	}
-	if v, ok := d.GetOk("max_test_timeout"); ok {
+	dom.DefaultErrorPenalty = d.Get("default_error_penalty").(int)
+	if v, ok := d.GetOkExists("max_test_timeout"); ok {
 		dom.MaxTestTimeout = v.(float64)
 	}
 	v := d.Get("cname_coalescing_enabled")
 	dom.CnameCoalescingEnabled = v.(bool)
-	if v, ok := d.GetOk("default_health_multiplier"); ok {
+	if v, ok := d.GetOkExists("default_health_multiplier"); ok {
 		dom.DefaultHealthMultiplier = v.(float64)
 	}
 	if v, ok := d.GetOk("servermonitor_pool"); ok {
@@ -476,33 +605,31 @@ func populateDomainObject(d *schema.ResourceData, dom *gtm.Domain) {
 	}
 	v = d.Get("load_feedback")
 	dom.LoadFeedback = v.(bool)
-	if v, ok := d.GetOk("min_ttl"); ok {
+	if v, ok := d.GetOkExists("min_ttl"); ok {
 		dom.MinTTL = int64(v.(int))
 	}
-	if v, ok := d.GetOk("default_max_unreachable_penalty"); ok {
+	if v, ok := d.GetOkExists("default_max_unreachable_penalty"); ok {
 		dom.DefaultMaxUnreachablePenalty = v.(int)
 	}
-	if v, ok := d.GetOk("default_health_threshold"); ok {
+	if v, ok := d.GetOkExists("default_health_threshold"); ok {
 		dom.DefaultHealthThreshold = v.(float64)
 	}
-	// Want??
-	//if v, ok := d.GetOk("last_modified_by"); ok { dom.LastModifiedBy = v.(string) }
-	// Want?
-	if v, ok := d.GetOk("modification_comments"); ok {
-		dom.ModificationComments = v.(string)
+	// Only sent when changed, so a comment of an earlier change is not repeated
+	dom.ModificationComments = ""
+	if d.HasChange("modification_comments") {
+		dom.ModificationComments = d.Get("modification_comments").(string)
 	}
-	if v, ok := d.GetOk("min_test_interval"); ok {
+	if v, ok := d.GetOkExists("min_test_interval"); ok {
 		dom.MinTestInterval = v.(int)
 	}
-	if v, ok := d.GetOk("ping_packet_size"); ok {
+	if v, ok := d.GetOkExists("ping_packet_size"); ok {
 		dom.PingPacketSize = v.(int)
 	}
 	if v, ok := d.GetOk("default_ssl_client_certificate"); ok {
 		dom.DefaultSslClientCertificate = v.(string)
 	}
-	if v, ok := d.GetOk("end_user_mapping_enabled"); ok {
-		dom.EndUserMappingEnabled = v.(bool)
-	}
+	v = d.Get("end_user_mapping_enabled")
+	dom.EndUserMappingEnabled = v.(bool)
 
 }
 
@@ -536,10 +663,20 @@ func populateTerraformState(d *schema.ResourceData, dom *gtm.Domain) {
 	d.Set("min_ttl", dom.MinTTL)
 	d.Set("default_max_unreachable_penalty", dom.DefaultMaxUnreachablePenalty)
 	d.Set("default_health_threshold", dom.DefaultHealthThreshold)
-	// Want??
-	//d.Set("last_modified_by", dom.LastModifiedBy)
-	// Want?
-	d.Set("modification_comments", dom.ModificationComments)
+	// modification_comments is kept as configured, since it describes the
+	// change being made rather than the domain
+	d.Set("last_modified_by", dom.LastModifiedBy)
+	statusList := make([]interface{}, 0)
+	if dom.Status != nil {
+		statusList = append(statusList, map[string]interface{}{
+			"change_id":               dom.Status.ChangeId,
+			"message":                 dom.Status.Message,
+			"passing_validation":      dom.Status.PassingValidation,
+			"propagation_status":      dom.Status.PropagationStatus,
+			"propagation_status_date": dom.Status.PropagationStatusDate,
+		})
+	}
+	d.Set("status", statusList)
 	d.Set("min_test_interval", dom.MinTestInterval)
 	d.Set("ping_packet_size", dom.PingPacketSize)
 	d.Set("default_ssl_client_certificate", dom.DefaultSslClientCertificate)
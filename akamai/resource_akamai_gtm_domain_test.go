@@ -7,6 +7,7 @@ import (
 
 	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/configgtm-v1_4"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -153,3 +154,92 @@ func testAccPreCheckTF(t *testing.T) {
 	HashiAcc = true
 
 }
+
+func TestImportGTMDomainChildren(t *testing.T) {
+	defaultDC := &gtm.DatacenterBase{Nickname: "Default Datacenter", DatacenterId: 5400}
+	dom := &gtm.Domain{
+		Name: gtm_test_domain,
+		Type: "weighted",
+		Datacenters: []*gtm.Datacenter{
+			{DatacenterId: 3131, Nickname: "tfexample_dc_1"},
+			{DatacenterId: 5400, Nickname: "Default Datacenter"},
+		},
+		Properties:     []*gtm.Property{{Name: "tfexample_prop_1", Type: "weighted-round-robin"}},
+		Resources:      []*gtm.Resource{{Name: "tfexample_resource_1", Type: "XML load object via HTTP"}},
+		AsMaps:         []*gtm.AsMap{{Name: "tfexample_as_1", DefaultDatacenter: defaultDC}},
+		GeographicMaps: []*gtm.GeoMap{{Name: "tfexample_geo_1", DefaultDatacenter: defaultDC}},
+		CidrMaps:       []*gtm.CidrMap{{Name: "tfexample_cidr_1", DefaultDatacenter: defaultDC}},
+	}
+
+	expected := map[string]string{
+		gtm_test_domain + ":3131":                 "akamai_gtm_datacenter",
+		gtm_test_domain + ":tfexample_prop_1":     "akamai_gtm_property",
+		gtm_test_domain + ":tfexample_resource_1": "akamai_gtm_resource",
+		gtm_test_domain + ":tfexample_as_1":       "akamai_gtm_asmap",
+		gtm_test_domain + ":tfexample_geo_1":      "akamai_gtm_geomap",
+		gtm_test_domain + ":tfexample_cidr_1":     "akamai_gtm_cidrmap",
+	}
+
+	results := importGTMDomainChildren(dom)
+	if len(results) != len(expected) {
+		t.Errorf("Value %v is invalid: expected %d resources, got %d", results, len(expected), len(results))
+	}
+	for _, r := range results {
+		state := r.State()
+		if expected[r.Id()] != state.Ephemeral.Type {
+			t.Errorf("Value %v is invalid: type %s", r.Id(), state.Ephemeral.Type)
+		}
+		if state.Attributes["domain"] != gtm_test_domain || state.Attributes["wait_on_complete"] != "true" {
+			t.Errorf("Value %v is invalid: attributes %v", r.Id(), state.Attributes)
+		}
+	}
+}
+
+func TestPopulateDomainObject(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceGTMv1Domain().Schema, map[string]interface{}{
+		"name":                          gtm_test_domain,
+		"type":                          "weighted",
+		"default_unreachable_threshold": 5000.0,
+		"min_pingable_region_fraction":  0.5,
+		"servermonitor_liveness_count":  3,
+		"load_imbalance_percentage":     0.0,
+		"modification_comments":         "Raise liveness count",
+	})
+
+	dom := &gtm.Domain{Name: gtm_test_domain, Type: "weighted", ServermonitorLivenessCount: 1, PingInterval: 30, LoadImbalancePercentage: 10, ModificationComments: "Earlier change"}
+	populateDomainObject(d, dom)
+	if dom.DefaultUnreachableThreshold != 5000 || dom.MinPingableRegionFraction != 0.5 || dom.ServermonitorLivenessCount != 3 {
+		t.Errorf("Value %v is invalid", dom)
+	}
+	if dom.PingInterval != 30 {
+		t.Errorf("Value %v is invalid: unset ping_interval should keep the remote value", dom.PingInterval)
+	}
+	if dom.LoadImbalancePercentage != 0 {
+		t.Errorf("Value %v is invalid: load_imbalance_percentage set to 0 should be applied", dom.LoadImbalancePercentage)
+	}
+	if dom.DefaultTimeoutPenalty != 25 || dom.DefaultErrorPenalty != 75 {
+		t.Errorf("Value %v %v is invalid: penalties should default to 25 and 75", dom.DefaultTimeoutPenalty, dom.DefaultErrorPenalty)
+	}
+	if dom.ModificationComments != "Raise liveness count" {
+		t.Errorf("Value %v is invalid", dom.ModificationComments)
+	}
+
+	dom.LastModifiedBy = "operator"
+	dom.Status = &gtm.ResponseStatus{ChangeId: "1234", PropagationStatus: "PENDING", PassingValidation: true}
+	populateTerraformState(d, dom)
+	if d.Get("last_modified_by") != "operator" || d.Get("status.0.propagation_status") != "PENDING" || d.Get("status.0.change_id") != "1234" {
+		t.Errorf("Value %v is invalid", d.Get("status"))
+	}
+	if d.Get("modification_comments") != "Raise liveness count" {
+		t.Errorf("Value %v is invalid: modification_comments should keep the configured value", d.Get("modification_comments"))
+	}
+
+	// An unchanged comment describes an earlier change and is not sent again
+	d.SetId(gtm_test_domain)
+	d = resourceGTMv1Domain().Data(d.State())
+	dom.ModificationComments = "Raise liveness count"
+	populateDomainObject(d, dom)
+	if dom.ModificationComments != "" {
+		t.Errorf("Value %v is invalid: unchanged modification_comments should not be sent", dom.ModificationComments)
+	}
+}
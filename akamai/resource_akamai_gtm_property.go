@@ -5,7 +5,9 @@ import (
 	"fmt"
 	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/configgtm-v1_4"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"log"
+	"math"
 	"strings"
 )
 
@@ -19,6 +21,7 @@ func resourceGTMv1Property() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceGTMv1PropertyImport,
 		},
+		CustomizeDiff: resourceGTMv1PropertyCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"domain": {
 				Type:     schema.TypeString,
@@ -214,8 +217,9 @@ func resourceGTMv1Property() *schema.Resource {
 							Required: true,
 						},
 						"error_penalty": {
-							Type:     schema.TypeFloat,
-							Optional: true,
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							ValidateFunc: validation.FloatBetween(0, math.MaxFloat64),
 						},
 						"peer_certificate_verification": {
 							Type:     schema.TypeBool,
@@ -223,8 +227,9 @@ func resourceGTMv1Property() *schema.Resource {
 							Default:  true,
 						},
 						"test_interval": {
-							Type:     schema.TypeInt,
-							Required: true,
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(gtmMinTestInterval),
 						},
 						"test_object": {
 							Type:     schema.TypeString,
@@ -255,21 +260,25 @@ func resourceGTMv1Property() *schema.Resource {
 							Optional: true,
 						},
 						"test_object_protocol": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(gtmLivenessTestProtocols, false),
 						},
 						"test_object_password": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
 						},
 						"test_object_port": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  80,
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      80,
+							ValidateFunc: validation.IntBetween(1, 65535),
 						},
 						"ssl_client_private_key": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
 						},
 						"ssl_client_certificate": {
 							Type:     schema.TypeString,
@@ -300,12 +309,14 @@ func resourceGTMv1Property() *schema.Resource {
 							Optional: true,
 						},
 						"test_timeout": {
-							Type:     schema.TypeFloat,
-							Required: true,
+							Type:         schema.TypeFloat,
+							Required:     true,
+							ValidateFunc: validation.FloatBetween(gtmMinTestTimeout, gtmMaxTestTimeout),
 						},
 						"timeout_penalty": {
-							Type:     schema.TypeFloat,
-							Optional: true,
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							ValidateFunc: validation.FloatBetween(0, math.MaxFloat64),
 						},
 						"answers_required": {
 							Type:     schema.TypeBool,
@@ -326,6 +337,133 @@ func resourceGTMv1Property() *schema.Resource {
 	}
 }
 
+const (
+	gtmMinTestInterval = 10
+	gtmMinTestTimeout  = 0.001
+	gtmMaxTestTimeout  = 60
+)
+
+var gtmLivenessTestProtocols = []string{"HTTP", "HTTPS", "FTP", "POP", "POPS", "SMTP", "SMTPS", "TCP", "TCPS", "DNS", "SIP", "SNMP"}
+
+// resourceGTMv1PropertyCustomizeDiff validates the liveness tests at plan
+// time, so tests GTM cannot run are not DENIED after propagation. Tests with
+// values that are not known yet are validated once they are.
+func resourceGTMv1PropertyCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+
+	for i, l := range d.Get("liveness_test").([]interface{}) {
+		lt, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _ := lt["name"].(string); isFailoverLivenessTest(name) {
+			return fmt.Errorf("liveness_test %q: names starting with %q are reserved for the health checks of akamai_dns_failover_record", name, failoverLivenessTestPrefix)
+		}
+		known := true
+		for k := range lt {
+			if !d.NewValueKnown(fmt.Sprintf("liveness_test.%d.%s", i, k)) {
+				known = false
+				break
+			}
+		}
+		if !known {
+			log.Printf("[DEBUG] [Akamai GTMv1] Liveness test %d is not known yet, skipping validation", i)
+			continue
+		}
+		if err := validateLivenessTest(lt); err != nil {
+			return fmt.Errorf("liveness_test %q: %s", lt["name"], err)
+		}
+	}
+	return nil
+}
+
+// validateLivenessTest checks that the fields of a liveness test apply to its
+// protocol, and that the test can complete within its interval.
+func validateLivenessTest(lt map[string]interface{}) error {
+
+	protocol := lt["test_object_protocol"].(string)
+	isHTTP := protocol == "HTTP" || protocol == "HTTPS"
+	isTLS := protocol == "HTTPS" || protocol == "POPS" || protocol == "SMTPS" || protocol == "TCPS"
+	isTCP := protocol == "TCP" || protocol == "TCPS"
+
+	interval := lt["test_interval"].(int)
+	timeout := lt["test_timeout"].(float64)
+	if timeout >= float64(interval) {
+		return fmt.Errorf("test_timeout %v must be less than test_interval %d", timeout, interval)
+	}
+
+	testObject := lt["test_object"].(string)
+	switch protocol {
+	case "HTTP", "HTTPS", "FTP":
+		if testObject == "" {
+			return fmt.Errorf("test_object must be the path to request for %s tests", protocol)
+		}
+	case "DNS":
+		if testObject == "" {
+			return fmt.Errorf("test_object must be the name to look up for DNS tests")
+		}
+		if lt["resource_type"].(string) == "" {
+			return fmt.Errorf("resource_type must be set for DNS tests")
+		}
+	}
+
+	if !isHTTP {
+		if headers, _ := lt["http_header"].([]interface{}); len(headers) > 0 {
+			return fmt.Errorf("http_header only applies to HTTP and HTTPS tests")
+		}
+		for _, k := range []string{"http_error3xx", "http_error4xx", "http_error5xx"} {
+			if lt[k].(bool) {
+				return fmt.Errorf("%s only applies to HTTP and HTTPS tests", k)
+			}
+		}
+	}
+
+	if protocol != "DNS" {
+		if lt["resource_type"].(string) != "" {
+			return fmt.Errorf("resource_type only applies to DNS tests")
+		}
+		for _, k := range []string{"answers_required", "recursion_requested"} {
+			if lt[k].(bool) {
+				return fmt.Errorf("%s only applies to DNS tests", k)
+			}
+		}
+	}
+
+	if !isTCP && lt["request_string"].(string) != "" {
+		return fmt.Errorf("request_string only applies to TCP and TCPS tests")
+	}
+
+	username, password := lt["test_object_username"].(string), lt["test_object_password"].(string)
+	switch protocol {
+	case "FTP":
+	case "POP", "POPS":
+		if username == "" || password == "" {
+			return fmt.Errorf("test_object_username and test_object_password must be set for %s tests", protocol)
+		}
+	default:
+		if username != "" || password != "" {
+			return fmt.Errorf("test_object_username and test_object_password only apply to FTP, POP and POPS tests")
+		}
+	}
+
+	certificate, key := lt["ssl_client_certificate"].(string), lt["ssl_client_private_key"].(string)
+	if certificate != "" || key != "" {
+		if !isTLS {
+			return fmt.Errorf("ssl_client_certificate and ssl_client_private_key only apply to HTTPS, POPS, SMTPS and TCPS tests")
+		}
+		if certificate == "" || key == "" {
+			return fmt.Errorf("ssl_client_certificate and ssl_client_private_key must be set together")
+		}
+		if !strings.Contains(certificate, "-----BEGIN CERTIFICATE-----") {
+			return fmt.Errorf("ssl_client_certificate must be a PEM encoded certificate")
+		}
+		if !strings.Contains(key, "PRIVATE KEY-----") {
+			return fmt.Errorf("ssl_client_private_key must be a PEM encoded private key")
+		}
+	}
+
+	return nil
+}
+
 // utility func to parse Terraform resource string id
 func parseResourceStringId(id string) (string, string, error) {
 
@@ -417,16 +555,33 @@ func resourceGTMv1PropertyUpdate(d *schema.ResourceData, meta interface{}) error
 	if err != nil {
 		return errors.New("Invalid property resource Id")
 	}
-	// Get existing property
-	existProp, err := gtm.GetProperty(property, domain)
-	if err != nil {
-		log.Printf("[ERROR] PropertyUpdate failed: %s", err.Error())
-		return err
-	}
-	log.Printf("[DEBUG] Updating [Akamai GTMv1] Property BEFORE: %v", existProp)
-	populatePropertyObject(d, existProp)
-	log.Printf("[DEBUG] Updating [Akamai GTMv1] Property PROPOSED: %v", existProp)
-	uStat, err := existProp.Update(domain)
+	// Read, change and write the property under the domain lock. Traffic
+	// targets that are not changed in the configuration are kept as they
+	// are live, so changes made with akamai_gtm_property_traffic_target in
+	// the meantime are not overwritten.
+	configured := gtm.NewProperty(property)
+	populateTrafficTargetObject(d, configured)
+	trafficTargetsChanged := d.HasChange("traffic_target")
+	uStat, err := updateGTMProperty(domain, property, func(existProp *gtm.Property) error {
+		log.Printf("[DEBUG] Updating [Akamai GTMv1] Property BEFORE: %v", existProp)
+		liveTargets := existProp.TrafficTargets
+		populatePropertyObject(d, existProp)
+		if !trafficTargetsChanged {
+			existProp.TrafficTargets = liveTargets
+		}
+		log.Printf("[DEBUG] Updating [Akamai GTMv1] Property PROPOSED: %v", existProp)
+		return nil
+	}, func(written *gtm.Property) bool {
+		if !trafficTargetsChanged {
+			return true
+		}
+		for _, tt := range configured.TrafficTargets {
+			if !trafficTargetsEqual(findTrafficTarget(written, tt.DatacenterId), tt) {
+				return false
+			}
+		}
+		return true
+	})
 	if err != nil {
 		log.Printf("[ERROR] PropertyUpdate failed: %s", err.Error())
 		return err
@@ -819,9 +974,17 @@ func populateTerraformStaticRRSetState(d *schema.ResourceData, prop *gtm.Propert
 
 }
 
-// Populate existing Livenesstest  object from resource data
+// Populate existing Livenesstest  object from resource data. Liveness tests of
+// failover record health checks are kept.
 func populateLivenessTestObject(d *schema.ResourceData, prop *gtm.Property) {
 
+	var failoverTests []*gtm.LivenessTest
+	for _, lt := range prop.LivenessTests {
+		if isFailoverLivenessTest(lt.Name) {
+			failoverTests = append(failoverTests, lt)
+		}
+	}
+
 	liveTestList := d.Get("liveness_test").([]interface{})
 	if liveTestList != nil {
 		liveTestObjList := make([]*gtm.LivenessTest, len(liveTestList)) // create new object list
@@ -864,7 +1027,7 @@ func populateLivenessTestObject(d *schema.ResourceData, prop *gtm.Property) {
 			}
 			liveTestObjList[i] = lt
 		}
-		prop.LivenessTests = liveTestObjList
+		prop.LivenessTests = append(liveTestObjList, failoverTests...)
 	}
 }
 
@@ -921,6 +1084,11 @@ func populateTerraformLivenessTestState(d *schema.ResourceData, prop *gtm.Proper
 		// remove object
 		delete(objectInventory, objIndex)
 	}
+	for name := range objectInventory {
+		if isFailoverLivenessTest(name) {
+			delete(objectInventory, name)
+		}
+	}
 	if len(objectInventory) > 0 {
 		log.Printf("[DEBUG] [Akamai GTMv1] Property LivenessTest objects left...")
 		// Objects not in the state yet. Add. Unfortunately, they not align with instance indices in the config
@@ -0,0 +1,403 @@
+package akamai
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	client "github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/configgtm-v1_4"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// GTM Property Traffic Targets
+//
+// A traffic target is part of its property, so each change reads the property,
+// changes the one target and writes the property back. Changes to the same
+// domain are serialized, and retried when GTM reports a conflict or another
+// client overwrote the change before it was verified.
+
+const (
+	gtmConflictRetries  = 5
+	gtmConflictInterval = 2 * time.Second
+)
+
+var gtmDomainLocks = newNamedLocks()
+
+func resourceGTMv1PropertyTrafficTarget() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGTMv1PropertyTrafficTargetCreate,
+		Read:   resourceGTMv1PropertyTrafficTargetRead,
+		Update: resourceGTMv1PropertyTrafficTargetUpdate,
+		Delete: resourceGTMv1PropertyTrafficTargetDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGTMv1PropertyTrafficTargetImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"property": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"datacenter_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"wait_on_complete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"weight": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				ValidateFunc: validation.FloatBetween(0, math.MaxFloat64),
+			},
+			"servers": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"handout_cname": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+// utility func to parse Terraform traffic target id
+func parseTrafficTargetId(id string) (string, string, int, error) {
+
+	parts := strings.Split(id, ":")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", -1, errors.New("Invalid traffic target id, expected domain:property:datacenter_id")
+	}
+	dcID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return "", "", -1, errors.New("Invalid traffic target id, expected domain:property:datacenter_id")
+	}
+
+	return parts[0], parts[1], dcID, nil
+}
+
+// Create a new GTM Property Traffic Target. It fails when the property already
+// has a target for the datacenter, which has to be imported instead.
+func resourceGTMv1PropertyTrafficTargetCreate(d *schema.ResourceData, meta interface{}) error {
+
+	domain := d.Get("domain").(string)
+	property := d.Get("property").(string)
+	tt := populateNewTrafficTargetObject(d)
+	log.Printf("[DEBUG] [Akamai GTMv1] Creating Property [%s] Traffic Target [%d] in domain [%s]", property, tt.DatacenterId, domain)
+
+	// Only the first attempt checks for an existing target, as a retry may
+	// find the target written by the attempt before it.
+	attempted := false
+	err := applyTrafficTargetChange(d, domain, property, func(prop *gtm.Property) error {
+		if !attempted && findTrafficTarget(prop, tt.DatacenterId) != nil {
+			return fmt.Errorf("property %s already has a traffic target for datacenter %d, import it with terraform import akamai_gtm_property_traffic_target.<name> %s:%s:%d", property, tt.DatacenterId, domain, property, tt.DatacenterId)
+		}
+		attempted = true
+		setTrafficTarget(prop, tt)
+		return nil
+	}, func(prop *gtm.Property) bool {
+		return trafficTargetsEqual(findTrafficTarget(prop, tt.DatacenterId), tt)
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s:%d", domain, property, tt.DatacenterId))
+	return resourceGTMv1PropertyTrafficTargetRead(d, meta)
+}
+
+// read traffic target. updates state with the target in the API result.
+func resourceGTMv1PropertyTrafficTargetRead(d *schema.ResourceData, meta interface{}) error {
+
+	log.Printf("[DEBUG] [Akamai GTMv1] READ")
+	log.Printf("[DEBUG] Reading [Akamai GTMv1] Property Traffic Target: %s", d.Id())
+	domain, property, dcID, err := parseTrafficTargetId(d.Id())
+	if err != nil {
+		return err
+	}
+	prop, err := gtm.GetProperty(property, domain)
+	if err != nil {
+		if cErr, ok := err.(gtm.CommonError); ok && cErr.NotFound() {
+			log.Printf("[WARNING] [Akamai GTMv1] Property [%s] not found, removing Traffic Target from state", property)
+			d.SetId("")
+			return nil
+		}
+		log.Printf("[ERROR] PropertyTrafficTargetRead failed: %s", err.Error())
+		return err
+	}
+	tt := findTrafficTarget(prop, dcID)
+	if tt == nil {
+		log.Printf("[WARNING] [Akamai GTMv1] Property [%s] Traffic Target [%d] not found, removing from state", property, dcID)
+		d.SetId("")
+		return nil
+	}
+	populateTerraformTrafficTargetObjectState(d, tt)
+	d.Set("domain", domain)
+	d.Set("property", property)
+	log.Printf("[DEBUG] [Akamai GTMv1] READ %v", tt)
+	return nil
+}
+
+// Update GTM Property Traffic Target
+func resourceGTMv1PropertyTrafficTargetUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	log.Printf("[DEBUG] [Akamai GTMv1] UPDATE")
+	log.Printf("[DEBUG] Updating [Akamai GTMv1] Property Traffic Target: %s", d.Id())
+	domain, property, _, err := parseTrafficTargetId(d.Id())
+	if err != nil {
+		return err
+	}
+	tt := populateNewTrafficTargetObject(d)
+
+	err = applyTrafficTargetChange(d, domain, property, func(prop *gtm.Property) error {
+		setTrafficTarget(prop, tt)
+		return nil
+	}, func(prop *gtm.Property) bool {
+		return trafficTargetsEqual(findTrafficTarget(prop, tt.DatacenterId), tt)
+	})
+	if err != nil {
+		return err
+	}
+
+	return resourceGTMv1PropertyTrafficTargetRead(d, meta)
+}
+
+// Import GTM Property Traffic Target.
+func resourceGTMv1PropertyTrafficTargetImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+
+	log.Printf("[INFO] [Akamai GTM] Property Traffic Target [%s] Import", d.Id())
+	domain, property, dcID, err := parseTrafficTargetId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	prop, err := gtm.GetProperty(property, domain)
+	if err != nil {
+		return nil, err
+	}
+	tt := findTrafficTarget(prop, dcID)
+	if tt == nil {
+		return nil, fmt.Errorf("Property %s has no traffic target for datacenter %d", property, dcID)
+	}
+	d.Set("domain", domain)
+	d.Set("property", property)
+	d.Set("wait_on_complete", true)
+	populateTerraformTrafficTargetObjectState(d, tt)
+
+	log.Printf("[INFO] [Akamai GTM] Property Traffic Target [%s] Imported", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
+
+// Delete GTM Property Traffic Target. The target is removed from the property.
+func resourceGTMv1PropertyTrafficTargetDelete(d *schema.ResourceData, meta interface{}) error {
+
+	log.Printf("[DEBUG] [Akamai GTMv1] DELETE")
+	log.Printf("[DEBUG] Deleting [Akamai GTMv1] Property Traffic Target: %s", d.Id())
+	domain, property, dcID, err := parseTrafficTargetId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	err = applyTrafficTargetChange(d, domain, property, func(prop *gtm.Property) error {
+		removeTrafficTarget(prop, dcID)
+		return nil
+	}, func(prop *gtm.Property) bool {
+		return findTrafficTarget(prop, dcID) == nil
+	})
+	if err != nil {
+		if cErr, ok := err.(gtm.CommonError); !ok || !cErr.NotFound() {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// applyTrafficTargetChange changes a property with updateGTMProperty, and
+// waits for the change to propagate when wait_on_complete is set.
+func applyTrafficTargetChange(d *schema.ResourceData, domain string, property string, change func(*gtm.Property) error, applied func(*gtm.Property) bool) error {
+
+	uStat, err := updateGTMProperty(domain, property, change, applied)
+	if err != nil {
+		log.Printf("[ERROR] PropertyTrafficTarget change failed: %s", err.Error())
+		return err
+	}
+	log.Printf("[DEBUG] [Akamai GTMv1] Property Traffic Target change status:")
+	log.Printf("[DEBUG] [Akamai GTMv1] %v", uStat)
+	if uStat.PropagationStatus == "DENIED" {
+		return errors.New(uStat.Message)
+	}
+	if d.Get("wait_on_complete").(bool) {
+		done, err := waitForCompletion(domain)
+		if done {
+			log.Printf("[INFO] [Akamai GTMv1] Property Traffic Target change completed")
+		} else {
+			if err == nil {
+				log.Printf("[INFO] [Akamai GTMv1] Property Traffic Target change pending")
+			} else {
+				log.Printf("[WARNING] [Akamai GTMv1] Property Traffic Target change failed [%s]", err.Error())
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// updateGTMProperty reads a property, changes it and writes it back while
+// holding the domain lock. Nothing is written when change returns an error. It retries when GTM reports a conflicting change,
+// or when the property read back after the write shows the change was not
+// applied because another client overwrote it.
+func updateGTMProperty(domain string, property string, change func(*gtm.Property) error, applied func(*gtm.Property) bool) (*gtm.ResponseStatus, error) {
+
+	unlock := gtmDomainLocks.lock(domain)
+	defer unlock()
+
+	interval := gtmConflictInterval
+	for attempt := 1; ; attempt++ {
+		prop, err := gtm.GetProperty(property, domain)
+		if err != nil {
+			return nil, err
+		}
+		if err := change(prop); err != nil {
+			return nil, err
+		}
+
+		uStat, err := prop.Update(domain)
+		if err == nil {
+			written, rErr := gtm.GetProperty(property, domain)
+			if rErr != nil {
+				return nil, rErr
+			}
+			if applied(written) {
+				return uStat, nil
+			}
+			err = fmt.Errorf("change to property %s was overwritten by another change", property)
+		} else if !isGTMConflict(err) {
+			return nil, err
+		}
+
+		if attempt == gtmConflictRetries {
+			return nil, err
+		}
+		log.Printf("[DEBUG] [Akamai GTMv1] Conflicting change to property %s, retrying in %v: %s", property, interval, err)
+		time.Sleep(interval)
+		interval *= 2
+	}
+}
+
+// isGTMConflict reports whether err is GTM rejecting a change because of
+// another change to the same domain.
+func isGTMConflict(err error) bool {
+	cErr, ok := err.(gtm.CommonError)
+	if !ok {
+		return false
+	}
+	apiErr, ok := cErr.GetItem("err").(client.APIError)
+	return ok && apiErr.Status == http.StatusConflict
+}
+
+// Create and populate a new traffic target object from resource data
+func populateNewTrafficTargetObject(d *schema.ResourceData) *gtm.TrafficTarget {
+
+	tt := &gtm.TrafficTarget{
+		DatacenterId: d.Get("datacenter_id").(int),
+		Enabled:      d.Get("enabled").(bool),
+		Weight:       d.Get("weight").(float64),
+		Name:         d.Get("name").(string),
+		HandoutCName: d.Get("handout_cname").(string),
+	}
+	for _, s := range d.Get("servers").([]interface{}) {
+		tt.Servers = append(tt.Servers, s.(string))
+	}
+	return tt
+}
+
+// Populate Terraform state from provided traffic target object
+func populateTerraformTrafficTargetObjectState(d *schema.ResourceData, tt *gtm.TrafficTarget) {
+
+	d.Set("datacenter_id", tt.DatacenterId)
+	d.Set("enabled", tt.Enabled)
+	d.Set("weight", tt.Weight)
+	d.Set("servers", tt.Servers)
+	d.Set("name", tt.Name)
+	d.Set("handout_cname", tt.HandoutCName)
+}
+
+// findTrafficTarget returns the traffic target of a property for a datacenter.
+func findTrafficTarget(prop *gtm.Property, dcID int) *gtm.TrafficTarget {
+
+	for _, tt := range prop.TrafficTargets {
+		if tt.DatacenterId == dcID {
+			return tt
+		}
+	}
+	return nil
+}
+
+// setTrafficTarget replaces the traffic target of a property for the
+// datacenter of tt, or adds tt when the property has none.
+func setTrafficTarget(prop *gtm.Property, tt *gtm.TrafficTarget) {
+
+	for i, existing := range prop.TrafficTargets {
+		if existing.DatacenterId == tt.DatacenterId {
+			prop.TrafficTargets[i] = tt
+			return
+		}
+	}
+	prop.TrafficTargets = append(prop.TrafficTargets, tt)
+}
+
+// removeTrafficTarget removes the traffic target of a property for a datacenter.
+func removeTrafficTarget(prop *gtm.Property, dcID int) {
+
+	targets := make([]*gtm.TrafficTarget, 0, len(prop.TrafficTargets))
+	for _, tt := range prop.TrafficTargets {
+		if tt.DatacenterId != dcID {
+			targets = append(targets, tt)
+		}
+	}
+	prop.TrafficTargets = targets
+}
+
+// trafficTargetsEqual compares two traffic targets, ignoring server order.
+func trafficTargetsEqual(a *gtm.TrafficTarget, b *gtm.TrafficTarget) bool {
+
+	if a == nil || b == nil {
+		return a == b
+	}
+	servers := func(tt *gtm.TrafficTarget) string {
+		s := append([]string(nil), tt.Servers...)
+		sort.Strings(s)
+		return strings.Join(s, ",")
+	}
+	return a.DatacenterId == b.DatacenterId && a.Enabled == b.Enabled && a.Weight == b.Weight &&
+		a.Name == b.Name && a.HandoutCName == b.HandoutCName && servers(a) == servers(b)
+}
@@ -0,0 +1,241 @@
+package akamai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/configgtm-v1_4"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+var testAccAkamaiGTMPropertyTrafficTargetConfig = fmt.Sprintf(`
+provider "akamai" {
+  gtm_section = "gtm"
+}
+
+data "akamai_contract" "contract" {
+}
+
+data "akamai_group" "group" {
+}
+
+resource "akamai_gtm_domain" "test_domain" {
+    name = "%s"
+    type = "weighted"
+    contract = data.akamai_contract.contract.id
+    group = data.akamai_group.group.id
+    wait_on_complete = false
+}
+
+resource "akamai_gtm_datacenter" "test_prop_datacenter" {
+    domain = akamai_gtm_domain.test_domain.name
+    nickname = "test_prop_datacenter1"
+    wait_on_complete = false
+}
+
+resource "akamai_gtm_datacenter" "test_canary_datacenter" {
+    domain = akamai_gtm_domain.test_domain.name
+    nickname = "test_canary_datacenter"
+    wait_on_complete = false
+}
+
+resource "akamai_gtm_property" "test_property" {
+    domain = akamai_gtm_domain.test_domain.name
+    name = "test_property"
+    type = "weighted-round-robin"
+    score_aggregation_type = "median"
+    handout_limit = 5
+    handout_mode = "normal"
+    wait_on_complete = false
+    traffic_target {
+        datacenter_id = akamai_gtm_datacenter.test_prop_datacenter.datacenter_id
+        enabled = true
+        weight = 90
+        servers = ["1.2.3.4"]
+    }
+    lifecycle {
+        ignore_changes = [traffic_target]
+    }
+}
+
+resource "akamai_gtm_property_traffic_target" "canary" {
+    domain = akamai_gtm_domain.test_domain.name
+    property = akamai_gtm_property.test_property.name
+    datacenter_id = akamai_gtm_datacenter.test_canary_datacenter.datacenter_id
+    weight = 10
+    servers = ["1.2.3.6"]
+    wait_on_complete = false
+}
+`, gtm_test_domain)
+
+func TestAccAkamaiGTMPropertyTrafficTarget_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAkamaiGTMPropertyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAkamaiGTMPropertyTrafficTargetConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("akamai_gtm_property_traffic_target.canary", "weight", "10"),
+					resource.TestCheckResourceAttr("akamai_gtm_property_traffic_target.canary", "enabled", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestParseTrafficTargetId(t *testing.T) {
+	domain, property, dcID, err := parseTrafficTargetId("example.akadns.net:www:3131")
+	if err != nil || domain != "example.akadns.net" || property != "www" || dcID != 3131 {
+		t.Errorf("Value %s %s %d is invalid: %v", domain, property, dcID, err)
+	}
+
+	for _, id := range []string{"example.akadns.net:www", "example.akadns.net:www:dc", ":www:3131", "example.akadns.net::3131"} {
+		if _, _, _, err := parseTrafficTargetId(id); err == nil {
+			t.Errorf("Value %v should be invalid", id)
+		}
+	}
+}
+
+func TestSetTrafficTarget(t *testing.T) {
+	prop := &gtm.Property{TrafficTargets: []*gtm.TrafficTarget{
+		{DatacenterId: 3131, Enabled: true, Weight: 90, Servers: []string{"1.2.3.4"}},
+		{DatacenterId: 3132, Enabled: true, Weight: 10, Servers: []string{"1.2.3.5"}},
+	}}
+
+	canary := &gtm.TrafficTarget{DatacenterId: 3132, Enabled: true, Weight: 50, Servers: []string{"1.2.3.6", "1.2.3.5"}}
+	setTrafficTarget(prop, canary)
+	if len(prop.TrafficTargets) != 2 || !trafficTargetsEqual(findTrafficTarget(prop, 3132), canary) || findTrafficTarget(prop, 3131).Weight != 90 {
+		t.Errorf("Value %v is invalid", prop.TrafficTargets)
+	}
+
+	setTrafficTarget(prop, &gtm.TrafficTarget{DatacenterId: 3133, Weight: 0})
+	if len(prop.TrafficTargets) != 3 {
+		t.Errorf("Value %v is invalid: target was not added", prop.TrafficTargets)
+	}
+
+	removeTrafficTarget(prop, 3133)
+	if len(prop.TrafficTargets) != 2 || findTrafficTarget(prop, 3133) != nil {
+		t.Errorf("Value %v is invalid: target was not removed", prop.TrafficTargets)
+	}
+}
+
+func TestTrafficTargetsEqual(t *testing.T) {
+	a := &gtm.TrafficTarget{DatacenterId: 3131, Enabled: true, Weight: 50, Servers: []string{"1.2.3.4", "1.2.3.5"}}
+	b := &gtm.TrafficTarget{DatacenterId: 3131, Enabled: true, Weight: 50, Servers: []string{"1.2.3.5", "1.2.3.4"}}
+	c := &gtm.TrafficTarget{DatacenterId: 3131, Enabled: true, Weight: 40, Servers: []string{"1.2.3.5", "1.2.3.4"}}
+
+	if !trafficTargetsEqual(a, b) {
+		t.Errorf("Value %v should equal %v", a, b)
+	}
+	if trafficTargetsEqual(a, c) || trafficTargetsEqual(a, nil) {
+		t.Errorf("Value %v should not equal %v", a, c)
+	}
+}
+
+func TestIsGTMConflict(t *testing.T) {
+	if isGTMConflict(errors.New("conflict")) {
+		t.Errorf("Value %v is invalid: not a GTM error", "conflict")
+	}
+	if isGTMConflict(gtm.CommonError{}) {
+		t.Errorf("Value %v is invalid: not found is not a conflict", gtm.CommonError{})
+	}
+}
+
+// testGTMPropertyServer serves a single GTM property, and counts the writes.
+func testGTMPropertyServer(t *testing.T, prop *gtm.Property) (*int, func()) {
+	writes := 0
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode(prop)
+		case "PUT":
+			writes++
+			*prop = gtm.Property{}
+			json.NewDecoder(r.Body).Decode(prop)
+			json.NewEncoder(w).Encode(gtm.PropertyResponse{Resource: prop, Status: &gtm.ResponseStatus{PropagationStatus: "PENDING"}})
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	httpClient, config := client.Client, gtm.Config
+	client.Client = srv.Client()
+	gtm.Init(edgegrid.Config{Host: srv.URL, ClientToken: "test", ClientSecret: "test", AccessToken: "test", MaxBody: 131072})
+	return &writes, func() {
+		client.Client, gtm.Config = httpClient, config
+		srv.Close()
+	}
+}
+
+func TestPropertyTrafficTargetCreateExisting(t *testing.T) {
+	prop := &gtm.Property{Name: "www", Type: "weighted-round-robin", TrafficTargets: []*gtm.TrafficTarget{
+		{DatacenterId: 3131, Enabled: true, Weight: 90, Servers: []string{"1.2.3.4"}},
+	}}
+	writes, done := testGTMPropertyServer(t, prop)
+	defer done()
+
+	d := schema.TestResourceDataRaw(t, resourceGTMv1PropertyTrafficTarget().Schema, map[string]interface{}{
+		"domain":           "example.akadns.net",
+		"property":         "www",
+		"datacenter_id":    3131,
+		"wait_on_complete": false,
+		"weight":           10,
+	})
+	err := resourceGTMv1PropertyTrafficTargetCreate(d, nil)
+	if err == nil || !strings.Contains(err.Error(), "terraform import") {
+		t.Errorf("Value %v is invalid: creating an existing traffic target should point to import", err)
+	}
+	if *writes != 0 || findTrafficTarget(prop, 3131).Weight != 90 {
+		t.Errorf("Value %v is invalid: the existing traffic target was changed", prop.TrafficTargets)
+	}
+}
+
+func TestPropertyUpdateKeepsTrafficTargets(t *testing.T) {
+	prop := &gtm.Property{Name: "www", Type: "weighted-round-robin", TrafficTargets: []*gtm.TrafficTarget{
+		{DatacenterId: 3131, Enabled: true, Weight: 90, Servers: []string{"1.2.3.4"}},
+	}}
+	_, done := testGTMPropertyServer(t, prop)
+	defer done()
+
+	d := resourceGTMv1Property().TestResourceData()
+	d.SetId("example.akadns.net:www")
+	d.Set("domain", "example.akadns.net")
+	d.Set("name", "www")
+	d.Set("type", "weighted-round-robin")
+	d.Set("wait_on_complete", false)
+	d.Set("traffic_target", []interface{}{map[string]interface{}{
+		"datacenter_id": 3131,
+		"enabled":       true,
+		"weight":        90.0,
+		"servers":       []interface{}{"1.2.3.4"},
+	}})
+	state := d.State()
+
+	// A traffic target resource adds a canary after the property was read.
+	prop.TrafficTargets = append(prop.TrafficTargets, &gtm.TrafficTarget{DatacenterId: 3132, Enabled: true, Weight: 10, Servers: []string{"1.2.3.5"}})
+
+	diff := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{
+		"handout_limit": {Old: "0", New: "5"},
+	}}
+	d, err := schema.InternalMap(resourceGTMv1Property().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("Value %v is invalid: %v", diff, err)
+	}
+	if err := resourceGTMv1PropertyUpdate(d, nil); err != nil {
+		t.Fatalf("Value %v is invalid: %v", diff, err)
+	}
+	if prop.HandoutLimit != 5 || findTrafficTarget(prop, 3132) == nil {
+		t.Errorf("Value %v is invalid: the canary traffic target should be kept", prop.TrafficTargets)
+	}
+}
@@ -0,0 +1,401 @@
+package akamai
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/configgtm-v1_4"
+	reportsgtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/reportsgtm-v1"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// GTM Weight Shift
+//
+// Moves weight from one traffic target of a property to another in steps.
+// Each step waits for the change to propagate, and for the configured interval
+// before the next step, and then checks the liveness of the destination
+// datacenter's servers. When any of them is down, or the datacenter has no
+// liveness data, the original weights are restored.
+
+const (
+	gtmLivenessRetries       = 3
+	gtmLivenessRetryInterval = 30 * time.Second
+)
+
+func resourceGTMv1WeightShift() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGTMv1WeightShiftCreate,
+		Read:   resourceGTMv1WeightShiftRead,
+		Update: resourceGTMv1WeightShiftUpdate,
+		Delete: resourceGTMv1WeightShiftDelete,
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"property": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"from_datacenter_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"to_datacenter_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"to_weight": {
+				Type:         schema.TypeFloat,
+				Required:     true,
+				ValidateFunc: validation.FloatBetween(0, math.MaxFloat64),
+			},
+			"increment": {
+				Type:         schema.TypeFloat,
+				Required:     true,
+				ValidateFunc: validation.FloatBetween(0.000001, math.MaxFloat64),
+			},
+			"interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      60,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"rollback": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"from_weight": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// utility func to parse Terraform weight shift id
+func parseWeightShiftId(id string) (string, string, int, int, error) {
+
+	parts := strings.Split(id, ":")
+	if len(parts) != 4 || parts[0] == "" || parts[1] == "" {
+		return "", "", -1, -1, errors.New("Invalid weight shift id, expected domain:property:from_datacenter_id:to_datacenter_id")
+	}
+	fromID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return "", "", -1, -1, errors.New("Invalid weight shift id, expected domain:property:from_datacenter_id:to_datacenter_id")
+	}
+	toID, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return "", "", -1, -1, errors.New("Invalid weight shift id, expected domain:property:from_datacenter_id:to_datacenter_id")
+	}
+
+	return parts[0], parts[1], fromID, toID, nil
+}
+
+// Create a new GTM Weight Shift. The weight is shifted before the resource is
+// recorded, so a shift that fails is retried by the next apply.
+func resourceGTMv1WeightShiftCreate(d *schema.ResourceData, meta interface{}) error {
+
+	domain := d.Get("domain").(string)
+	property := d.Get("property").(string)
+	fromID := d.Get("from_datacenter_id").(int)
+	toID := d.Get("to_datacenter_id").(int)
+	if fromID == toID {
+		return errors.New("from_datacenter_id and to_datacenter_id must differ")
+	}
+	log.Printf("[DEBUG] [Akamai GTMv1] Shifting weight of Property [%s] in domain [%s] from datacenter [%d] to [%d]", property, domain, fromID, toID)
+
+	if err := shiftGTMWeight(d, domain, property, fromID, toID); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s:%d:%d", domain, property, fromID, toID))
+	return resourceGTMv1WeightShiftRead(d, meta)
+}
+
+// read weight shift. updates state with the current weights of both traffic targets.
+func resourceGTMv1WeightShiftRead(d *schema.ResourceData, meta interface{}) error {
+
+	log.Printf("[DEBUG] [Akamai GTMv1] READ")
+	log.Printf("[DEBUG] Reading [Akamai GTMv1] Weight Shift: %s", d.Id())
+	domain, property, fromID, toID, err := parseWeightShiftId(d.Id())
+	if err != nil {
+		return err
+	}
+	prop, err := gtm.GetProperty(property, domain)
+	if err != nil {
+		if cErr, ok := err.(gtm.CommonError); ok && cErr.NotFound() {
+			log.Printf("[WARNING] [Akamai GTMv1] Property [%s] not found, removing Weight Shift from state", property)
+			d.SetId("")
+			return nil
+		}
+		log.Printf("[ERROR] WeightShiftRead failed: %s", err.Error())
+		return err
+	}
+	fromWeight, toWeight := trafficTargetWeights(prop, fromID, toID)
+	d.Set("domain", domain)
+	d.Set("property", property)
+	d.Set("from_datacenter_id", fromID)
+	d.Set("to_datacenter_id", toID)
+	d.Set("from_weight", fromWeight)
+	d.Set("to_weight", toWeight)
+	log.Printf("[DEBUG] [Akamai GTMv1] READ weights %v -> %v", fromWeight, toWeight)
+	return nil
+}
+
+// Update GTM Weight Shift. A changed to_weight is shifted to from the current
+// weights. When the shift fails, the previous state is kept.
+func resourceGTMv1WeightShiftUpdate(d *schema.ResourceData, meta interface{}) error {
+
+	log.Printf("[DEBUG] [Akamai GTMv1] UPDATE")
+	log.Printf("[DEBUG] Updating [Akamai GTMv1] Weight Shift: %s", d.Id())
+	domain, property, fromID, toID, err := parseWeightShiftId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	d.Partial(true)
+	if err := shiftGTMWeight(d, domain, property, fromID, toID); err != nil {
+		return err
+	}
+	d.Partial(false)
+
+	return resourceGTMv1WeightShiftRead(d, meta)
+}
+
+// Delete GTM Weight Shift. Only removes the resource from state; the weights
+// are left as they are.
+func resourceGTMv1WeightShiftDelete(d *schema.ResourceData, meta interface{}) error {
+
+	log.Printf("[DEBUG] [Akamai GTMv1] DELETE")
+	log.Printf("[DEBUG] Deleting [Akamai GTMv1] Weight Shift: %s", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// shiftGTMWeight moves weight from one traffic target to the other until the
+// destination has to_weight, keeping the sum of both weights. After each step
+// it waits for propagation, and for the interval unless it was the last step,
+// and checks the liveness of the destination. When the destination is down or
+// its liveness cannot be read, the original weights are restored if rollback
+// is set.
+func shiftGTMWeight(d *schema.ResourceData, domain string, property string, fromID int, toID int) error {
+
+	prop, err := gtm.GetProperty(property, domain)
+	if err != nil {
+		return err
+	}
+	if findTrafficTarget(prop, fromID) == nil || findTrafficTarget(prop, toID) == nil {
+		return fmt.Errorf("Property %s needs traffic targets for datacenters %d and %d", property, fromID, toID)
+	}
+	fromWeight, toWeight := trafficTargetWeights(prop, fromID, toID)
+
+	steps, err := weightShiftSteps(fromWeight, toWeight, d.Get("to_weight").(float64), d.Get("increment").(float64))
+	if err != nil {
+		return err
+	}
+	interval := time.Duration(d.Get("interval").(int)) * time.Second
+
+	for i, step := range steps {
+		log.Printf("[INFO] [Akamai GTMv1] Weight Shift step %d/%d of Property [%s]: weights %v -> %v", i+1, len(steps), property, step[0], step[1])
+		if err := setGTMWeights(domain, property, fromID, toID, step[0], step[1]); err != nil {
+			return err
+		}
+		if i < len(steps)-1 {
+			time.Sleep(interval)
+		}
+
+		err := checkDatacenterLiveness(domain, property, toID)
+		if err == nil {
+			continue
+		}
+		if !d.Get("rollback").(bool) {
+			return fmt.Errorf("weight shift of property %s stopped at weights %v -> %v: %s", property, step[0], step[1], err)
+		}
+		log.Printf("[WARNING] [Akamai GTMv1] Weight Shift of Property [%s] failed, rolling back: %s", property, err.Error())
+		if rErr := setGTMWeights(domain, property, fromID, toID, fromWeight, toWeight); rErr != nil {
+			return fmt.Errorf("weight shift of property %s failed: %s; rollback to weights %v -> %v failed: %s", property, err, fromWeight, toWeight, rErr)
+		}
+		return fmt.Errorf("weight shift of property %s was rolled back to weights %v -> %v: %s", property, fromWeight, toWeight, err)
+	}
+
+	return nil
+}
+
+// setGTMWeights sets the weights of two traffic targets of a property and
+// waits for the change to propagate.
+func setGTMWeights(domain string, property string, fromID int, toID int, fromWeight float64, toWeight float64) error {
+
+	uStat, err := updateGTMProperty(domain, property, func(prop *gtm.Property) error {
+		return setTrafficTargetWeights(prop, fromID, toID, fromWeight, toWeight)
+	}, func(prop *gtm.Property) bool {
+		f, t := trafficTargetWeights(prop, fromID, toID)
+		return f == fromWeight && t == toWeight
+	})
+	if err != nil {
+		log.Printf("[ERROR] WeightShift change failed: %s", err.Error())
+		return err
+	}
+	log.Printf("[DEBUG] [Akamai GTMv1] Weight Shift change status:")
+	log.Printf("[DEBUG] [Akamai GTMv1] %v", uStat)
+	if uStat.PropagationStatus == "DENIED" {
+		return errors.New(uStat.Message)
+	}
+
+	done, err := waitForCompletion(domain)
+	if done {
+		log.Printf("[INFO] [Akamai GTMv1] Weight Shift change completed")
+	} else {
+		if err == nil {
+			log.Printf("[INFO] [Akamai GTMv1] Weight Shift change pending")
+		} else {
+			log.Printf("[WARNING] [Akamai GTMv1] Weight Shift change failed [%s]", err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkDatacenterLiveness returns an error when the most recent liveness
+// report of a property shows servers of the datacenter as down. Reports that
+// cannot be read or have no servers of the datacenter are retried, as the
+// report may not include the datacenter yet, and fail the check when the
+// retries are exhausted.
+func checkDatacenterLiveness(domain string, property string, dcID int) error {
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var stat *reportsgtm.IPStatusPerProperty
+		stat, err = reportsgtm.GetIpStatusPerProperty(domain, property, map[string]string{
+			"mostRecent":   "true",
+			"datacenterId": strconv.Itoa(dcID),
+		})
+		if err == nil {
+			err = datacenterLiveness(stat, dcID)
+			if err == nil || !isNoLivenessData(err) {
+				return err
+			}
+		} else {
+			err = fmt.Errorf("unable to read liveness of datacenter %d: %s", dcID, err)
+		}
+		if attempt == gtmLivenessRetries {
+			return err
+		}
+		log.Printf("[WARNING] [Akamai GTMv1] Liveness check %d/%d of Property [%s] failed, retrying in %s: %s", attempt, gtmLivenessRetries, property, gtmLivenessRetryInterval, err.Error())
+		time.Sleep(gtmLivenessRetryInterval)
+	}
+}
+
+// noLivenessDataError is returned when a liveness report has no servers of a
+// datacenter.
+type noLivenessDataError struct {
+	dcID int
+}
+
+func (e noLivenessDataError) Error() string {
+	return fmt.Sprintf("no liveness data for datacenter %d", e.dcID)
+}
+
+func isNoLivenessData(err error) bool {
+	_, ok := err.(noLivenessDataError)
+	return ok
+}
+
+// datacenterLiveness returns an error when the most recent row of a liveness
+// report shows servers of the datacenter as down, or has none of its servers.
+func datacenterLiveness(stat *reportsgtm.IPStatusPerProperty, dcID int) error {
+
+	alive, down := datacenterServers(stat, dcID)
+	if len(down) > 0 {
+		return fmt.Errorf("servers of datacenter %d are down: %s", dcID, strings.Join(down, ", "))
+	}
+	if len(alive) == 0 {
+		return noLivenessDataError{dcID: dcID}
+	}
+	return nil
+}
+
+// datacenterServers returns the servers of a datacenter that are alive and
+// down in the most recent row of a liveness report.
+func datacenterServers(stat *reportsgtm.IPStatusPerProperty, dcID int) ([]string, []string) {
+
+	var alive, down []string
+	if stat == nil || len(stat.DataRows) == 0 {
+		return alive, down
+	}
+	for _, dc := range stat.DataRows[len(stat.DataRows)-1].Datacenters {
+		if dc.DatacenterId != dcID {
+			continue
+		}
+		for _, ip := range dc.IPs {
+			if ip.Alive {
+				alive = append(alive, ip.Ip)
+			} else {
+				down = append(down, ip.Ip)
+			}
+		}
+	}
+	return alive, down
+}
+
+// trafficTargetWeights returns the weights of two traffic targets of a
+// property. A missing target has no weight.
+func trafficTargetWeights(prop *gtm.Property, fromID int, toID int) (float64, float64) {
+
+	var fromWeight, toWeight float64
+	if tt := findTrafficTarget(prop, fromID); tt != nil {
+		fromWeight = tt.Weight
+	}
+	if tt := findTrafficTarget(prop, toID); tt != nil {
+		toWeight = tt.Weight
+	}
+	return fromWeight, toWeight
+}
+
+// setTrafficTargetWeights sets the weights of two traffic targets of a
+// property. It fails when either target was removed in the meantime.
+func setTrafficTargetWeights(prop *gtm.Property, fromID int, toID int, fromWeight float64, toWeight float64) error {
+
+	from, to := findTrafficTarget(prop, fromID), findTrafficTarget(prop, toID)
+	if from == nil || to == nil {
+		return fmt.Errorf("Property %s needs traffic targets for datacenters %d and %d", prop.Name, fromID, toID)
+	}
+	from.Weight = fromWeight
+	to.Weight = toWeight
+	return nil
+}
+
+// weightShiftSteps returns the from and to weights of each step that moves
+// the to weight to target by increment, keeping the sum of both weights.
+func weightShiftSteps(fromWeight float64, toWeight float64, target float64, increment float64) ([][2]float64, error) {
+
+	total := fromWeight + toWeight
+	if target > total {
+		return nil, fmt.Errorf("to_weight %v exceeds the combined weight %v of both traffic targets", target, total)
+	}
+
+	var steps [][2]float64
+	for toWeight != target {
+		if math.Abs(target-toWeight) <= increment {
+			toWeight = target
+		} else if target > toWeight {
+			toWeight += increment
+		} else {
+			toWeight -= increment
+		}
+		steps = append(steps, [2]float64{total - toWeight, toWeight})
+	}
+	return steps, nil
+}
@@ -0,0 +1,188 @@
+package akamai
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	gtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/configgtm-v1_4"
+	reportsgtm "github.com/akamai/AkamaiOPEN-edgegrid-golang/reportsgtm-v1"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+var testAccAkamaiGTMWeightShiftConfig = fmt.Sprintf(`
+provider "akamai" {
+  gtm_section = "gtm"
+}
+
+data "akamai_contract" "contract" {
+}
+
+data "akamai_group" "group" {
+}
+
+resource "akamai_gtm_domain" "test_domain" {
+    name = "%s"
+    type = "weighted"
+    contract = data.akamai_contract.contract.id
+    group = data.akamai_group.group.id
+    wait_on_complete = false
+}
+
+resource "akamai_gtm_datacenter" "test_blue_datacenter" {
+    domain = akamai_gtm_domain.test_domain.name
+    nickname = "test_blue_datacenter"
+    wait_on_complete = false
+}
+
+resource "akamai_gtm_datacenter" "test_green_datacenter" {
+    domain = akamai_gtm_domain.test_domain.name
+    nickname = "test_green_datacenter"
+    wait_on_complete = false
+}
+
+resource "akamai_gtm_property" "test_property" {
+    domain = akamai_gtm_domain.test_domain.name
+    name = "test_property"
+    type = "weighted-round-robin"
+    score_aggregation_type = "median"
+    handout_limit = 5
+    handout_mode = "normal"
+    wait_on_complete = false
+    traffic_target {
+        datacenter_id = akamai_gtm_datacenter.test_blue_datacenter.datacenter_id
+        enabled = true
+        weight = 100
+        servers = ["1.2.3.4"]
+    }
+    traffic_target {
+        datacenter_id = akamai_gtm_datacenter.test_green_datacenter.datacenter_id
+        enabled = true
+        weight = 0
+        servers = ["1.2.3.5"]
+    }
+    lifecycle {
+        ignore_changes = [traffic_target]
+    }
+}
+
+resource "akamai_gtm_weight_shift" "blue_green" {
+    domain = akamai_gtm_domain.test_domain.name
+    property = akamai_gtm_property.test_property.name
+    from_datacenter_id = akamai_gtm_datacenter.test_blue_datacenter.datacenter_id
+    to_datacenter_id = akamai_gtm_datacenter.test_green_datacenter.datacenter_id
+    to_weight = 100
+    increment = 50
+    interval = 0
+    rollback = false
+}
+`, gtm_test_domain)
+
+func TestAccAkamaiGTMWeightShift_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAkamaiGTMPropertyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAkamaiGTMWeightShiftConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("akamai_gtm_weight_shift.blue_green", "to_weight", "100"),
+					resource.TestCheckResourceAttr("akamai_gtm_weight_shift.blue_green", "from_weight", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestParseWeightShiftId(t *testing.T) {
+	domain, property, fromID, toID, err := parseWeightShiftId("example.akadns.net:www:3131:3132")
+	if err != nil || domain != "example.akadns.net" || property != "www" || fromID != 3131 || toID != 3132 {
+		t.Errorf("Value %s %s %d %d is invalid: %v", domain, property, fromID, toID, err)
+	}
+
+	for _, id := range []string{"example.akadns.net:www:3131", "example.akadns.net:www:3131:dc", ":www:3131:3132"} {
+		if _, _, _, _, err := parseWeightShiftId(id); err == nil {
+			t.Errorf("Value %v should be invalid", id)
+		}
+	}
+}
+
+func TestWeightShiftSteps(t *testing.T) {
+	steps, err := weightShiftSteps(100, 0, 100, 30)
+	expected := [][2]float64{{70, 30}, {40, 60}, {10, 90}, {0, 100}}
+	if err != nil || !reflect.DeepEqual(steps, expected) {
+		t.Errorf("Value %v is invalid, expected %v: %v", steps, expected, err)
+	}
+
+	steps, err = weightShiftSteps(20, 80, 50, 20)
+	expected = [][2]float64{{40, 60}, {50, 50}}
+	if err != nil || !reflect.DeepEqual(steps, expected) {
+		t.Errorf("Value %v is invalid, expected %v: %v", steps, expected, err)
+	}
+
+	if steps, err := weightShiftSteps(50, 50, 50, 10); err != nil || len(steps) != 0 {
+		t.Errorf("Value %v is invalid, expected no steps: %v", steps, err)
+	}
+	if _, err := weightShiftSteps(50, 0, 60, 10); err == nil {
+		t.Errorf("Value %v should exceed the combined weight", 60)
+	}
+}
+
+func TestSetTrafficTargetWeights(t *testing.T) {
+	prop := &gtm.Property{Name: "www", TrafficTargets: []*gtm.TrafficTarget{
+		{DatacenterId: 3131, Weight: 90},
+		{DatacenterId: 3132, Weight: 10},
+	}}
+
+	if err := setTrafficTargetWeights(prop, 3131, 3132, 60, 40); err != nil {
+		t.Errorf("Value %v is invalid: %v", prop.TrafficTargets, err)
+	}
+	if f, to := trafficTargetWeights(prop, 3131, 3132); f != 60 || to != 40 {
+		t.Errorf("Value %v %v is invalid", f, to)
+	}
+
+	removeTrafficTarget(prop, 3132)
+	if err := setTrafficTargetWeights(prop, 3131, 3132, 30, 70); err == nil {
+		t.Errorf("Value %v should be invalid: target 3132 was removed", prop.TrafficTargets)
+	}
+	if f, _ := trafficTargetWeights(prop, 3131, 3132); f != 60 {
+		t.Errorf("Value %v is invalid: weight changed without both targets", f)
+	}
+}
+
+func TestDatacenterLiveness(t *testing.T) {
+	stat := &reportsgtm.IPStatusPerProperty{DataRows: []*reportsgtm.IpStatPerPropData{
+		{Datacenters: []*reportsgtm.IpStatPerPropDRow{
+			{DatacenterId: 3132, IPs: []*reportsgtm.IpStatIp{{Ip: "1.2.3.5", Alive: false}}},
+		}},
+		{Datacenters: []*reportsgtm.IpStatPerPropDRow{
+			{DatacenterId: 3131, IPs: []*reportsgtm.IpStatIp{{Ip: "1.2.3.4", Alive: false}}},
+			{DatacenterId: 3132, IPs: []*reportsgtm.IpStatIp{{Ip: "1.2.3.5", Alive: true}, {Ip: "1.2.3.6", Alive: false}}},
+		}},
+	}}
+
+	if err := datacenterLiveness(stat, 3132); err == nil || isNoLivenessData(err) {
+		t.Errorf("Value %v is invalid: datacenter with down servers should fail", err)
+	}
+	if err := datacenterLiveness(stat, 3133); !isNoLivenessData(err) {
+		t.Errorf("Value %v is invalid: datacenter without servers should have no liveness data", err)
+	}
+	if err := datacenterLiveness(&reportsgtm.IPStatusPerProperty{}, 3132); !isNoLivenessData(err) {
+		t.Errorf("Value %v is invalid: empty report should have no liveness data", err)
+	}
+	alive := &reportsgtm.IPStatusPerProperty{DataRows: []*reportsgtm.IpStatPerPropData{
+		{Datacenters: []*reportsgtm.IpStatPerPropDRow{
+			{DatacenterId: 3132, IPs: []*reportsgtm.IpStatIp{{Ip: "1.2.3.5", Alive: true}}},
+		}},
+	}}
+	if err := datacenterLiveness(alive, 3132); err != nil {
+		t.Errorf("Value %v is invalid", err)
+	}
+	if alive, down := datacenterServers(stat, 3131); len(alive) != 0 || !reflect.DeepEqual(down, []string{"1.2.3.4"}) {
+		t.Errorf("Value %v %v is invalid", alive, down)
+	}
+	if alive, _ := datacenterServers(stat, 3132); !reflect.DeepEqual(alive, []string{"1.2.3.5"}) {
+		t.Errorf("Value %v is invalid", alive)
+	}
+}
@@ -92,21 +92,36 @@ var akamaiPropertySchema = map[string]*schema.Schema{
 		Elem:     &schema.Schema{Type: schema.TypeString},
 	},
 
-	// Will get added to the default rule
+	// Will get added to the default rule, or to the child rule named by "rule"
 	"origin": {
 		Type:     schema.TypeSet,
 		Optional: true,
 		Elem: &schema.Resource{
 			Schema: map[string]*schema.Schema{
+				"rule": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  "",
+				},
+				"origin_type": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "CUSTOMER",
+					ValidateFunc: validation.StringInSlice([]string{"CUSTOMER", "NET_STORAGE"}, false),
+				},
 				"hostname": {
 					Type:     schema.TypeString,
-					Required: true,
+					Optional: true,
 				},
 				"port": {
 					Type:     schema.TypeInt,
 					Optional: true,
 					Default:  80,
 				},
+				"https_port": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
 				"forward_hostname": {
 					Type:     schema.TypeString,
 					Optional: true,
@@ -127,6 +142,73 @@ var akamaiPropertySchema = map[string]*schema.Schema{
 					Optional: true,
 					Default:  false,
 				},
+				"net_storage": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"download_domain_name": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"cp_code": {
+								Type:     schema.TypeInt,
+								Required: true,
+							},
+							"id": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+							"name": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+				// origin_sni is a string so that leaving it unset keeps the
+				// value from the rules, which a bool cannot tell from false.
+				"origin_sni": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.StringInSlice([]string{"true", "false"}, false),
+				},
+				"verification_mode": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.StringInSlice([]string{"PLATFORM_SETTINGS", "CUSTOM", "THIRD_PARTY"}, false),
+				},
+				"custom_valid_cn_values": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"origin_certs_to_honor": {
+					Type:     schema.TypeString,
+					Optional: true,
+					ValidateFunc: validation.StringInSlice([]string{
+						"COMBO",
+						"STANDARD_CERTIFICATE_AUTHORITIES",
+						"CUSTOM_CERTIFICATE_AUTHORITIES",
+						"CUSTOM_CERTIFICATES",
+					}, false),
+				},
+				"standard_certificate_authorities": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"custom_certificate_authorities": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"custom_certificates": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
 			},
 		},
 	},
@@ -141,8 +223,15 @@ var akamaiPropertySchema = map[string]*schema.Schema{
 		DiffSuppressFunc: suppressEquivalentJsonDiffs,
 	},
 	"variables": {
-		Type:     schema.TypeString,
-		Optional: true,
+		Type:             schema.TypeString,
+		Optional:         true,
+		DiffSuppressFunc: suppressEquivalentVariablesDiffs,
+	},
+	"sensitive_variables": {
+		Type:      schema.TypeMap,
+		Optional:  true,
+		Sensitive: true,
+		Elem:      &schema.Schema{Type: schema.TypeString},
 	},
 	"rulessha": &schema.Schema{
 		Type:     schema.TypeString,
@@ -285,9 +374,14 @@ func getRules(d *schema.ResourceData, property *papi.Property, contract *papi.Co
 	}
 
 	log.Printf("[DEBUG] updateStandardBehaviors")
-	updateStandardBehaviors(rules, cpCode, origin)
+	if err := updateStandardBehaviors(rules, cpCode, origin); err != nil {
+		return nil, err
+	}
 	log.Printf("[DEBUG] fixupPerformanceBehaviors")
 	fixupPerformanceBehaviors(rules)
+	if err := resolveIncludeBehaviors(rules.Rule, property.ContractID, property.GroupID); err != nil {
+		return nil, err
+	}
 
 	return rules, nil
 }
@@ -465,6 +559,10 @@ func resourcePropertyRead(d *schema.ResourceData, meta interface{}) error {
 	sha1hashAPI := getSHAString(string(jsonBody))
 	log.Printf("[DEBUG] READ SHA from Json %s\n", sha1hashAPI)
 
+	if err := nameIncludeBehaviors(rules.Rule, property.ContractID, property.GroupID); err != nil {
+		return err
+	}
+	jsonBody, err = jsonhooks.Marshal(rules)
 	if err == nil {
 		log.Printf("[DEBUG] READ Rules from API : %s\n", string(jsonBody))
 		d.Set("rules", string(jsonBody))
@@ -576,6 +674,13 @@ func resourceCustomDiffCustomizeDiff(d *schema.ResourceDiff, meta interface{}) e
 	// Note that this gets put into state after the update, regardless of whether
 	// or not anything is acted upon in the diff.
 
+	if d.NewValueKnown("rules") && d.NewValueKnown("variables") {
+		undeclared := undeclaredPropertyVariables(d.Get("rules").(string), d.Get("variables").(string))
+		if len(undeclared) > 0 {
+			return fmt.Errorf("rules reference undeclared variables: %s", strings.Join(undeclared, ", "))
+		}
+	}
+
 	old, new := d.GetChange("rules")
 
 	log.Println("[DEBUG] resourceCustomDiffCustomizeDiff OLD " + old.(string))
@@ -734,7 +839,7 @@ func getProduct(d *schema.ResourceData, contract *papi.Contract) (*papi.Product,
 	return product, nil
 }
 
-func createOrigin(d interface{}) (*papi.OptionValue, error) {
+func createOrigin(d interface{}) (map[string]papi.OptionValue, error) {
 	log.Println("[DEBUG] Setting origin")
 	var origin interface{}
 	var ok bool
@@ -748,49 +853,129 @@ func createOrigin(d interface{}) (*papi.OptionValue, error) {
 		origin, ok = d.(*schema.ResourceData).GetOk("origin")
 	}
 
-	//if origin, ok := rd.GetOk("origin"); ok {
-	if ok {
-		originConfig := origin.(*schema.Set).List()[0].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	origins := make(map[string]papi.OptionValue)
+	for _, o := range origin.(*schema.Set).List() {
+		originConfig := o.(map[string]interface{})
+
+		rule := originConfig["rule"].(string)
+		if _, ok := origins[rule]; ok {
+			return nil, fmt.Errorf("more than one origin targets rule %q", rule)
+		}
+
+		originValues, err := createOriginValues(originConfig)
+		if err != nil {
+			return nil, err
+		}
+		origins[rule] = originValues
+	}
 
-		forwardHostname, forwardHostnameOk := originConfig["forward_hostname"].(string)
-		originValues := make(map[string]interface{})
+	return origins, nil
+}
+
+// createOriginValues builds the options of an origin behavior from a single
+// origin block.
+func createOriginValues(originConfig map[string]interface{}) (papi.OptionValue, error) {
+	forwardHostname, forwardHostnameOk := originConfig["forward_hostname"].(string)
+	originValues := make(map[string]interface{})
+
+	originType := originConfig["origin_type"].(string)
+	originValues["originType"] = originType
 
-		originValues["originType"] = "CUSTOMER"
-		if val, ok := originConfig["hostname"]; ok {
-			originValues["hostname"] = val.(string)
+	switch originType {
+	case "NET_STORAGE":
+		netStorage, ok := originConfig["net_storage"].([]interface{})
+		if !ok || len(netStorage) == 0 || netStorage[0] == nil {
+			return nil, errors.New("a net_storage block is required for NET_STORAGE origins")
+		}
+		netStorageConfig := netStorage[0].(map[string]interface{})
+		netStorageValues := map[string]interface{}{
+			"downloadDomainName": netStorageConfig["download_domain_name"].(string),
+			"cpCode":             netStorageConfig["cp_code"].(int),
+		}
+		if id, ok := netStorageConfig["id"].(int); ok && id != 0 {
+			netStorageValues["id"] = id
 		}
+		if name, ok := netStorageConfig["name"].(string); ok && name != "" {
+			netStorageValues["name"] = name
+		}
+		originValues["netStorage"] = netStorageValues
+	default:
+		hostname, _ := originConfig["hostname"].(string)
+		if hostname == "" {
+			return nil, errors.New("hostname is required for CUSTOMER origins")
+		}
+		originValues["hostname"] = hostname
 
 		if val, ok := originConfig["port"]; ok {
 			originValues["httpPort"] = val.(int)
 		}
 
-		if val, ok := originConfig["cache_key_hostname"]; ok {
-			originValues["cacheKeyHostname"] = val.(string)
+		// HTTPS settings are only set when configured, so they don't
+		// overwrite the origin certificate settings of the rules.
+		if val, ok := originConfig["https_port"].(int); ok && val != 0 {
+			originValues["httpsPort"] = val
 		}
 
-		if val, ok := originConfig["compress"]; ok {
-			originValues["compress"] = val.(bool)
+		if val, ok := originConfig["origin_sni"].(string); ok && val != "" {
+			originValues["originSni"] = val == "true"
 		}
 
-		if val, ok := originConfig["enable_true_client_ip"]; ok {
-			originValues["enableTrueClientIp"] = val.(bool)
+		verificationMode, _ := originConfig["verification_mode"].(string)
+		if verificationMode != "" {
+			originValues["verificationMode"] = verificationMode
 		}
+		if verificationMode == "CUSTOM" {
+			originValues["customValidCnValues"] = originConfig["custom_valid_cn_values"].([]interface{})
 
-		if forwardHostnameOk && (forwardHostname == "ORIGIN_HOSTNAME" || forwardHostname == "REQUEST_HOST_HEADER") {
-			log.Println("[DEBUG] Setting non-custom forward hostname")
+			certsToHonor := originConfig["origin_certs_to_honor"].(string)
+			if certsToHonor == "" {
+				return nil, errors.New("origin_certs_to_honor is required when verification_mode is CUSTOM")
+			}
+			originValues["originCertsToHonor"] = certsToHonor
+			originValues["standardCertificateAuthorities"] = originConfig["standard_certificate_authorities"].([]interface{})
+			originValues["customCertificateAuthorities"] = pemCertificateOptions(originConfig["custom_certificate_authorities"].([]interface{}))
+			originValues["customCertificates"] = pemCertificateOptions(originConfig["custom_certificates"].([]interface{}))
+		}
+	}
 
-			originValues["forwardHostHeader"] = forwardHostname
-		} else if forwardHostnameOk {
-			log.Println("[DEBUG] Setting custom forward hostname")
+	if val, ok := originConfig["cache_key_hostname"]; ok {
+		originValues["cacheKeyHostname"] = val.(string)
+	}
 
-			originValues["forwardHostHeader"] = "CUSTOM"
-			originValues["customForwardHostHeader"] = forwardHostname
-		}
+	if val, ok := originConfig["compress"]; ok {
+		originValues["compress"] = val.(bool)
+	}
+
+	if val, ok := originConfig["enable_true_client_ip"]; ok {
+		originValues["enableTrueClientIp"] = val.(bool)
+	}
+
+	if forwardHostnameOk && (forwardHostname == "ORIGIN_HOSTNAME" || forwardHostname == "REQUEST_HOST_HEADER") {
+		log.Println("[DEBUG] Setting non-custom forward hostname")
+
+		originValues["forwardHostHeader"] = forwardHostname
+	} else if forwardHostnameOk {
+		log.Println("[DEBUG] Setting custom forward hostname")
 
-		ov := papi.OptionValue(originValues)
-		return &ov, nil
+		originValues["forwardHostHeader"] = "CUSTOM"
+		originValues["customForwardHostHeader"] = forwardHostname
 	}
-	return nil, nil
+
+	return papi.OptionValue(originValues), nil
+}
+
+// pemCertificateOptions wraps PEM encoded certificates in the object form
+// expected by the origin behavior.
+func pemCertificateOptions(pems []interface{}) []interface{} {
+	certs := make([]interface{}, 0, len(pems))
+	for _, pem := range pems {
+		certs = append(certs, map[string]interface{}{"pemEncodedCert": pem.(string)})
+	}
+	return certs
 }
 
 func fixupPerformanceBehaviors(rules *papi.Rules) {
@@ -824,7 +1009,7 @@ func fixupAdaptiveImageCompression(rules *papi.Rules) {
 	log.Println("[DEBUG] Start Fixing Up adaptiveImageCompression Behavior  ", behavior)
 }
 
-func updateStandardBehaviors(rules *papi.Rules, cpCode *papi.CpCode, origin *papi.OptionValue) {
+func updateStandardBehaviors(rules *papi.Rules, cpCode *papi.CpCode, origins map[string]papi.OptionValue) error {
 	log.Printf("[DEBUG] cpCode: %#v", cpCode)
 	if cpCode != nil {
 		b := papi.NewBehavior()
@@ -837,12 +1022,19 @@ func updateStandardBehaviors(rules *papi.Rules, cpCode *papi.CpCode, origin *pap
 		rules.Rule.MergeBehavior(b)
 	}
 
-	if origin != nil {
+	for ruleName, origin := range origins {
+		rule, err := rules.FindRule(strings.ToLower(ruleName))
+		if err != nil {
+			return fmt.Errorf("origin rule %q not found in property rules", ruleName)
+		}
+
 		b := papi.NewBehavior()
 		b.Name = "origin"
-		b.Options = *origin
-		rules.Rule.MergeBehavior(b)
+		b.Options = origin
+		rule.MergeBehavior(b)
 	}
+
+	return nil
 }
 
 func unmarshalRulesFromJSON(d *schema.ResourceData, propertyRules *papi.Rules) {
@@ -962,7 +1154,9 @@ func unmarshalRulesFromJSON(d *schema.ResourceData, propertyRules *papi.Rules) {
 			return true // keep iterating
 		}) // for loop rules
 
-		// ADD vars from variables resource
+		// ADD vars from variables resource, with the values of sensitive
+		// variables from sensitive_variables
+		sensitiveVariables := d.Get("sensitive_variables").(map[string]interface{})
 		jsonvars, ok := d.GetOk("variables")
 		if ok {
 			//			log.Println("unmarshalRulesFromJson VARS from JSON ", jsonvars)
@@ -982,6 +1176,9 @@ func unmarshalRulesFromJSON(d *schema.ResourceData, propertyRules *papi.Rules) {
 					newVariable.Value = variableMap["value"].(string)
 					newVariable.Hidden = variableMap["hidden"].(bool)
 					newVariable.Sensitive = variableMap["sensitive"].(bool)
+					if value, ok := sensitiveVariables[newVariable.Name]; ok {
+						newVariable.Value = value.(string)
+					}
 					propertyRules.Rule.AddVariable(newVariable)
 				}
 				return true
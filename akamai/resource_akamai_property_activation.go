@@ -78,20 +78,7 @@ func resourcePropertyActivationCreate(d *schema.ResourceData, meta interface{})
 		d.Set("status", string(activation.Status))
 		go activation.PollStatus(property)
 
-	polling:
-		for activation.Status != papi.StatusActive {
-			select {
-			case statusChanged := <-activation.StatusChange:
-				log.Printf("[DEBUG] Property Status: %s\n", activation.Status)
-				if statusChanged == false {
-					break polling
-				}
-				continue polling
-			case <-time.After(time.Minute * 90):
-				log.Println("[DEBUG] Activation Timeout (90 minutes)")
-				break polling
-			}
-		}
+		waitForActivation(activation.StatusChange, func() papi.StatusValue { return activation.Status }, papi.StatusActive)
 	} else {
 		d.SetId("none")
 	}
@@ -131,20 +118,7 @@ func resourcePropertyActivationDelete(d *schema.ResourceData, meta interface{})
 
 		go activation.PollStatus(property)
 
-	polling:
-		for activation.Status != papi.StatusActive {
-			select {
-			case statusChanged := <-activation.StatusChange:
-				log.Printf("[DEBUG] Property Status: %s\n", activation.Status)
-				if statusChanged == false {
-					break polling
-				}
-				continue polling
-			case <-time.After(time.Minute * 90):
-				log.Println("[DEBUG] Activation Timeout (90 minutes)")
-				break polling
-			}
-		}
+		waitForActivation(activation.StatusChange, func() papi.StatusValue { return activation.Status }, papi.StatusActive)
 
 		d.Set("status", string(activation.Status))
 	}
@@ -250,20 +224,7 @@ func resourcePropertyActivationUpdate(d *schema.ResourceData, meta interface{})
 
 		go activation.PollStatus(property)
 
-	polling:
-		for activation.Status != papi.StatusActive {
-			select {
-			case statusChanged := <-activation.StatusChange:
-				log.Printf("[DEBUG] Property Status: %s\n", activation.Status)
-				if statusChanged == false {
-					break polling
-				}
-				continue polling
-			case <-time.After(time.Minute * 90):
-				log.Println("[DEBUG] Activation Timeout (90 minutes)")
-				break polling
-			}
-		}
+		waitForActivation(activation.StatusChange, func() papi.StatusValue { return activation.Status }, papi.StatusActive)
 		d.Set("version", activation.PropertyVersion)
 		d.Set("status", string(activation.Status))
 	} else {
@@ -378,3 +339,24 @@ func findExistingActivation(property *papi.Property, activation *papi.Activation
 
 	return nil, nil
 }
+
+// waitForActivation waits for an activation polled in the background to reach
+// the final status, for at most 90 minutes. The poller reports each status
+// change on statusChange, and false once it gives up. It returns whether the
+// final status was reached.
+func waitForActivation(statusChange chan bool, status func() papi.StatusValue, final papi.StatusValue) bool {
+	timeout := time.After(time.Minute * 90)
+	for status() != final {
+		select {
+		case statusChanged := <-statusChange:
+			log.Printf("[DEBUG] Property Status: %s\n", status())
+			if statusChanged == false {
+				return false
+			}
+		case <-timeout:
+			log.Println("[DEBUG] Activation Timeout (90 minutes)")
+			return false
+		}
+	}
+	return true
+}
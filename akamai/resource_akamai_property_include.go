@@ -0,0 +1,313 @@
+package akamai
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/tidwall/gjson"
+)
+
+func resourcePropertyInclude() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourcePropertyIncludeCreate,
+		Read:          resourcePropertyIncludeRead,
+		Update:        resourcePropertyIncludeUpdate,
+		Delete:        resourcePropertyIncludeDelete,
+		CustomizeDiff: resourcePropertyIncludeCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			State: resourcePropertyIncludeImport,
+		},
+		Schema: akamaiPropertyIncludeSchema,
+	}
+}
+
+var akamaiPropertyIncludeSchema = map[string]*schema.Schema{
+	"contract": &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+	},
+	"group": &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+	},
+	"product": &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+	},
+	"name": &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+	},
+	"type": &schema.Schema{
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.StringInSlice([]string{"MICROSERVICES", "COMMON_SETTINGS"}, false),
+	},
+	"rule_format": &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+	},
+	"rules": {
+		Type:             schema.TypeString,
+		Optional:         true,
+		ValidateFunc:     validation.ValidateJsonString,
+		DiffSuppressFunc: suppressEquivalentIncludeRulesDiffs,
+	},
+	"version": &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true,
+	},
+	"staging_version": &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true,
+	},
+	"production_version": &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true,
+	},
+}
+
+func resourcePropertyIncludeCreate(d *schema.ResourceData, meta interface{}) error {
+	contractID := d.Get("contract").(string)
+	groupID := d.Get("group").(string)
+
+	include := &propertyInclude{
+		IncludeName: d.Get("name").(string),
+		IncludeType: d.Get("type").(string),
+		ProductID:   d.Get("product").(string),
+	}
+
+	if ruleFormat, ok := d.GetOk("rule_format"); ok {
+		include.RuleFormat = ruleFormat.(string)
+	} else {
+		ruleFormats := papi.NewRuleFormats()
+		latest, err := ruleFormats.GetLatest()
+		if err != nil {
+			return err
+		}
+		include.RuleFormat = latest
+	}
+
+	log.Printf("[DEBUG] Creating include %s", include.IncludeName)
+	includeID, err := createPropertyInclude(include, contractID, groupID)
+	if err != nil {
+		return err
+	}
+	d.SetId(includeID)
+
+	if err := savePropertyIncludeRulesFromConfig(d); err != nil {
+		return err
+	}
+
+	log.Println("[DEBUG] Done")
+	return resourcePropertyIncludeRead(d, meta)
+}
+
+func resourcePropertyIncludeRead(d *schema.ResourceData, meta interface{}) error {
+	contractID := d.Get("contract").(string)
+	groupID := d.Get("group").(string)
+
+	include, err := getPropertyInclude(d.Id(), contractID, groupID)
+	if err != nil {
+		if apiErr, ok := err.(client.APIError); ok && apiErr.Status == 404 {
+			log.Printf("[WARN] Include %s not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("name", include.IncludeName)
+	d.Set("type", include.IncludeType)
+	d.Set("version", include.LatestVersion)
+	d.Set("staging_version", 0)
+	if include.StagingVersion != nil {
+		d.Set("staging_version", *include.StagingVersion)
+	}
+	d.Set("production_version", 0)
+	if include.ProductionVersion != nil {
+		d.Set("production_version", *include.ProductionVersion)
+	}
+
+	rules, err := getPropertyIncludeRules(include.IncludeID, include.LatestVersion, contractID, groupID)
+	if err != nil {
+		return err
+	}
+	d.Set("rule_format", rules.RuleFormat)
+
+	jsonBody, err := marshalIncludeRules(rules.Rule)
+	if err != nil {
+		return err
+	}
+	d.Set("rules", jsonBody)
+
+	return nil
+}
+
+func resourcePropertyIncludeUpdate(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("rules") || d.HasChange("rule_format") {
+		if err := savePropertyIncludeRulesFromConfig(d); err != nil {
+			return err
+		}
+	}
+
+	return resourcePropertyIncludeRead(d, meta)
+}
+
+// resourcePropertyIncludeCustomizeDiff plans a new version when the rules
+// change, since saving them may create one. Activations referencing version
+// then activate the version that holds the new rules.
+func resourcePropertyIncludeCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	if d.HasChange("rules") || d.HasChange("rule_format") {
+		log.Printf("[DEBUG] Include %s rules changed, version is computed", d.Id())
+		return d.SetNewComputed("version")
+	}
+
+	return nil
+}
+
+func resourcePropertyIncludeDelete(d *schema.ResourceData, meta interface{}) error {
+	contractID := d.Get("contract").(string)
+	groupID := d.Get("group").(string)
+
+	include, err := getPropertyInclude(d.Id(), contractID, groupID)
+	if err != nil {
+		return err
+	}
+
+	if include.StagingVersion != nil {
+		return fmt.Errorf("include is still active on %s and cannot be deleted", papi.NetworkStaging)
+	}
+
+	if include.ProductionVersion != nil {
+		return fmt.Errorf("include is still active on %s and cannot be deleted", papi.NetworkProduction)
+	}
+
+	if err := deletePropertyInclude(d.Id(), contractID, groupID); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourcePropertyIncludeImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportID(d.Id(), 3, "include_id:contract:group")
+	if err != nil {
+		return nil, err
+	}
+
+	include, err := getPropertyInclude(parts[0], parts[1], parts[2])
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("contract", parts[1])
+	d.Set("group", parts[2])
+	d.Set("product", include.ProductID)
+	d.SetId(include.IncludeID)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// savePropertyIncludeRulesFromConfig writes the configured rules to an
+// editable version of the include.
+func savePropertyIncludeRulesFromConfig(d *schema.ResourceData) error {
+	contractID := d.Get("contract").(string)
+	groupID := d.Get("group").(string)
+
+	include, err := getPropertyInclude(d.Id(), contractID, groupID)
+	if err != nil {
+		return err
+	}
+
+	version, err := ensureEditableIncludeVersion(include, contractID, groupID)
+	if err != nil {
+		return err
+	}
+
+	rules := &propertyIncludeRules{Rule: papi.NewRule()}
+	rules.Rule.Name = "default"
+	if v, ok := d.GetOk("rules"); ok {
+		rule, err := unmarshalIncludeRules(v.(string))
+		if err != nil {
+			return err
+		}
+		rules.Rule = rule
+	}
+
+	if err := resolveIncludeBehaviors(rules.Rule, contractID, groupID); err != nil {
+		return err
+	}
+
+	if ruleFormat, ok := d.GetOk("rule_format"); ok {
+		rules.RuleFormat = ruleFormat.(string)
+	} else {
+		rules.RuleFormat = include.RuleFormat
+	}
+
+	log.Printf("[DEBUG] Saving include %s version %d rules", include.IncludeID, version)
+	return savePropertyIncludeRules(include.IncludeID, version, contractID, groupID, rules)
+}
+
+// unmarshalIncludeRules parses a {"rules": {...}} document into a rule tree.
+func unmarshalIncludeRules(rulesJSON string) (*papi.Rule, error) {
+	result := gjson.Get(rulesJSON, "rules")
+	if !result.Exists() {
+		return nil, errors.New("include rules must contain a top-level \"rules\" object")
+	}
+
+	rule := papi.NewRule()
+	if err := jsonhooks.Unmarshal([]byte(result.Raw), rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func marshalIncludeRules(rule *papi.Rule) (string, error) {
+	jsonBody, err := jsonhooks.Marshal(map[string]interface{}{"rules": rule})
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBody), nil
+}
+
+// suppressEquivalentIncludeRulesDiffs compares include rules after a round trip
+// through the rule tree, so formatting and unknown keys don't cause diffs.
+func suppressEquivalentIncludeRulesDiffs(k, old, new string, d *schema.ResourceData) bool {
+	oldRule, err := unmarshalIncludeRules(old)
+	if err != nil {
+		return false
+	}
+	newRule, err := unmarshalIncludeRules(new)
+	if err != nil {
+		return false
+	}
+
+	oldJSON, err := marshalIncludeRules(oldRule)
+	if err != nil {
+		return false
+	}
+	newJSON, err := marshalIncludeRules(newRule)
+	if err != nil {
+		return false
+	}
+
+	return jsonBytesEqual([]byte(oldJSON), []byte(newJSON))
+}
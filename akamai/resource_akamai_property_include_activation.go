@@ -0,0 +1,252 @@
+package akamai
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourcePropertyIncludeActivation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePropertyIncludeActivationCreate,
+		Read:   resourcePropertyIncludeActivationRead,
+		Update: resourcePropertyIncludeActivationUpdate,
+		Delete: resourcePropertyIncludeActivationDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourcePropertyIncludeActivationImport,
+		},
+		Schema: akamaiPropertyIncludeActivationSchema,
+	}
+}
+
+var akamaiPropertyIncludeActivationSchema = map[string]*schema.Schema{
+	"include": &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+	},
+	"contract": &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+	},
+	"group": &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+	},
+	"version": &schema.Schema{
+		Type:     schema.TypeInt,
+		Required: true,
+	},
+	"network": &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  "staging",
+		ForceNew: true,
+		StateFunc: func(val interface{}) string {
+			return strings.ToUpper(val.(string))
+		},
+		ValidateFunc: validation.StringInSlice([]string{string(papi.NetworkStaging), string(papi.NetworkProduction)}, true),
+	},
+	"contact": &schema.Schema{
+		Type:     schema.TypeSet,
+		Required: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	},
+	"note": &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  "Using Terraform",
+	},
+	"status": &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	},
+}
+
+func resourcePropertyIncludeActivationCreate(d *schema.ResourceData, meta interface{}) error {
+	activation, err := activatePropertyInclude(d, papi.ActivationTypeActivate)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(activation.ActivationID)
+	d.Set("status", string(activation.Status))
+
+	log.Println("[DEBUG] Done")
+	return resourcePropertyIncludeActivationRead(d, meta)
+}
+
+func resourcePropertyIncludeActivationRead(d *schema.ResourceData, meta interface{}) error {
+	includeID := d.Get("include").(string)
+	contractID := d.Get("contract").(string)
+	groupID := d.Get("group").(string)
+
+	include, err := getPropertyInclude(includeID, contractID, groupID)
+	if err != nil {
+		return err
+	}
+
+	// Another version may have been activated on the network since, in which
+	// case the activated version is read back so the plan reactivates ours.
+	network := papi.NetworkValue(strings.ToUpper(d.Get("network").(string)))
+	activeVersion := include.ProductionVersion
+	if network == papi.NetworkStaging {
+		activeVersion = include.StagingVersion
+	}
+	if activeVersion == nil {
+		log.Printf("[WARN] Include %s has no active version on %s, removing from state", includeID, network)
+		d.SetId("")
+		return nil
+	}
+	version := d.Get("version").(int)
+	if *activeVersion != version {
+		log.Printf("[WARN] Include %s v%d is active on %s instead of v%d", includeID, *activeVersion, network, version)
+		version = *activeVersion
+	}
+
+	activations, err := getPropertyIncludeActivations(includeID, contractID, groupID)
+	if err != nil {
+		return err
+	}
+
+	for _, activation := range activations {
+		if activation.Network == network && activation.IncludeVersion == version && activation.ActivationType == papi.ActivationTypeActivate {
+			d.SetId(activation.ActivationID)
+			d.Set("version", version)
+			d.Set("status", string(activation.Status))
+			return nil
+		}
+	}
+
+	log.Printf("[WARN] No activation of include %s v%d found on %s, removing from state", includeID, version, network)
+	d.SetId("")
+	return nil
+}
+
+func resourcePropertyIncludeActivationUpdate(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("version") {
+		activation, err := activatePropertyInclude(d, papi.ActivationTypeActivate)
+		if err != nil {
+			return err
+		}
+
+		d.SetId(activation.ActivationID)
+		d.Set("status", string(activation.Status))
+	}
+
+	return resourcePropertyIncludeActivationRead(d, meta)
+}
+
+func resourcePropertyIncludeActivationDelete(d *schema.ResourceData, meta interface{}) error {
+	contractID := d.Get("contract").(string)
+	groupID := d.Get("group").(string)
+
+	include, err := getPropertyInclude(d.Get("include").(string), contractID, groupID)
+	if err != nil {
+		return err
+	}
+
+	activeVersion := include.ProductionVersion
+	if papi.NetworkValue(strings.ToUpper(d.Get("network").(string))) == papi.NetworkStaging {
+		activeVersion = include.StagingVersion
+	}
+
+	version := d.Get("version").(int)
+	if activeVersion != nil && *activeVersion == version {
+		log.Printf("[DEBUG] Deactivating include %s v%d", include.IncludeID, version)
+		if _, err := activatePropertyInclude(d, papi.ActivationTypeDeactivate); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourcePropertyIncludeActivationImport imports the activation of the version
+// currently active on a network, using an include_id:contract:group:network ID.
+func resourcePropertyIncludeActivationImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportID(d.Id(), 4, "include_id:contract:group:network")
+	if err != nil {
+		return nil, err
+	}
+	includeID, contractID, groupID := parts[0], parts[1], parts[2]
+	network := papi.NetworkValue(strings.ToUpper(parts[3]))
+
+	include, err := getPropertyInclude(includeID, contractID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	activeVersion := include.ProductionVersion
+	if network == papi.NetworkStaging {
+		activeVersion = include.StagingVersion
+	}
+	if activeVersion == nil {
+		return nil, fmt.Errorf("include %s has no active version on %s", includeID, network)
+	}
+
+	activations, err := getPropertyIncludeActivations(includeID, contractID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	for _, activation := range activations {
+		if activation.Network != network || activation.IncludeVersion != *activeVersion || activation.ActivationType != papi.ActivationTypeActivate {
+			continue
+		}
+
+		d.Set("include", includeID)
+		d.Set("contract", contractID)
+		d.Set("group", groupID)
+		d.Set("network", string(network))
+		d.Set("version", activation.IncludeVersion)
+		d.Set("contact", activation.NotifyEmails)
+		d.Set("note", activation.Note)
+		d.Set("status", string(activation.Status))
+		d.SetId(activation.ActivationID)
+		return []*schema.ResourceData{d}, nil
+	}
+
+	return nil, fmt.Errorf("no activation of include %s v%d found on %s", includeID, *activeVersion, network)
+}
+
+// activatePropertyInclude submits an include (de)activation for the configured
+// version and waits for it to complete.
+func activatePropertyInclude(d *schema.ResourceData, activationType papi.ActivationValue) (*propertyIncludeActivation, error) {
+	includeID := d.Get("include").(string)
+	contractID := d.Get("contract").(string)
+	groupID := d.Get("group").(string)
+
+	activation := &propertyIncludeActivation{
+		ActivationType:         activationType,
+		IncludeVersion:         d.Get("version").(int),
+		Network:                papi.NetworkValue(strings.ToUpper(d.Get("network").(string))),
+		Note:                   d.Get("note").(string),
+		AcknowledgeAllWarnings: true,
+	}
+	for _, email := range d.Get("contact").(*schema.Set).List() {
+		activation.NotifyEmails = append(activation.NotifyEmails, email.(string))
+	}
+
+	if err := createPropertyIncludeActivation(includeID, contractID, groupID, activation); err != nil {
+		return nil, fmt.Errorf("unable to %s include %s v%d on %s: %s", strings.ToLower(string(activationType)), includeID, activation.IncludeVersion, activation.Network, err)
+	}
+	log.Printf("[DEBUG] Include %s submitted successfully: %s", strings.ToLower(string(activationType)), activation.ActivationID)
+
+	final := papi.StatusActive
+	if activationType == papi.ActivationTypeDeactivate {
+		final = papi.StatusDeactivated
+	}
+	statusChange := make(chan bool, 1)
+	go pollIncludeActivation(includeID, contractID, groupID, activation, final, statusChange)
+	if !waitForActivation(statusChange, func() papi.StatusValue { return activation.Status }, final) {
+		return nil, fmt.Errorf("include %s %s %s did not complete: %s", includeID, strings.ToLower(string(activationType)), activation.ActivationID, activation.Status)
+	}
+
+	return activation, nil
+}
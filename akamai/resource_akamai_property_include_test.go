@@ -0,0 +1,236 @@
+package akamai
+
+import (
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+var testAccAkamaiPropertyIncludeConfig = fmt.Sprintf(`
+provider "akamai" {
+  papi_section = "papi"
+}
+
+data "akamai_contract" "contract" {
+}
+
+data "akamai_group" "group" {
+}
+
+resource "akamai_property_include" "include" {
+	name = "terraform-test-include"
+	contract = "${data.akamai_contract.contract.id}"
+	group = "${data.akamai_group.group.id}"
+	product = "prd_SPM"
+	type = "MICROSERVICES"
+	rule_format = "v2020-03-04"
+	rules = <<EOF
+{
+	"rules": {
+		"name": "default",
+		"behaviors": [
+			{
+				"name": "caching",
+				"options": {
+					"behavior": "MAX_AGE",
+					"mustRevalidate": false,
+					"ttl": "1d"
+				}
+			}
+		]
+	}
+}
+EOF
+}
+
+resource "akamai_property_include_activation" "include_activation" {
+	include = "${akamai_property_include.include.id}"
+	contract = "${data.akamai_contract.contract.id}"
+	group = "${data.akamai_group.group.id}"
+	version = "${akamai_property_include.include.version}"
+	network = "STAGING"
+	contact = ["user@exampleterraform.io"]
+}
+`)
+
+func TestAccAkamaiPropertyInclude_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAkamaiPropertyIncludeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAkamaiPropertyIncludeConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAkamaiPropertyIncludeExists,
+					resource.TestCheckResourceAttr("akamai_property_include.include", "name", "terraform-test-include"),
+					resource.TestCheckResourceAttr("akamai_property_include_activation.include_activation", "status", string(papi.StatusActive)),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAkamaiPropertyIncludeDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "akamai_property_include" {
+			continue
+		}
+
+		log.Printf("[DEBUG] [Akamai PropertyInclude] Include Destroy")
+		_, err := getPropertyInclude(rs.Primary.ID, rs.Primary.Attributes["contract"], rs.Primary.Attributes["group"])
+		if err == nil {
+			return fmt.Errorf("include %s still exists", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testAccCheckAkamaiPropertyIncludeExists(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "akamai_property_include" {
+			continue
+		}
+
+		_, err := getPropertyInclude(rs.Primary.ID, rs.Primary.Attributes["contract"], rs.Primary.Attributes["group"])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestLinkID(t *testing.T) {
+	cases := map[string]string{
+		"/papi/v1/includes/inc_12345?contractId=ctr_1&groupId=grp_2":                "inc_12345",
+		"/papi/v1/includes/inc_12345/activations/atv_678?contractId=ctr_1&groupId=": "atv_678",
+		"/papi/v1/includes/inc_12345":                                               "inc_12345",
+	}
+
+	for link, expected := range cases {
+		if got := linkID(link); got != expected {
+			t.Errorf("linkID(%q) = %q, expected %q", link, got, expected)
+		}
+	}
+}
+
+func TestUnmarshalIncludeRules(t *testing.T) {
+	rule, err := unmarshalIncludeRules(`{"rules": {"name": "default", "behaviors": [{"name": "include", "options": {"id": "inc_1"}}]}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if rule.Name != "default" || len(rule.Behaviors) != 1 || rule.Behaviors[0].Options["id"] != "inc_1" {
+		t.Errorf("unexpected rule tree: %#v", rule)
+	}
+
+	if _, err := unmarshalIncludeRules(`{"name": "default"}`); err == nil {
+		t.Error("expected an error for rules without a top-level \"rules\" object")
+	}
+}
+
+func TestCanonicalIncludeBehaviors(t *testing.T) {
+	include := func(options papi.OptionValue) *papi.Rule {
+		rule := papi.NewRule()
+		rule.Name = "default"
+		rule.Behaviors = []*papi.Behavior{{Name: "include", Options: options}}
+		return rule
+	}
+
+	state := include(papi.OptionValue{"id": "inc_1", "name": "common"})
+	byName := include(papi.OptionValue{"name": "common"})
+	byID := include(papi.OptionValue{"id": "inc_1"})
+	canonicalIncludeBehaviors(state, byName, byID)
+
+	for _, rule := range []*papi.Rule{state, byName, byID} {
+		if name := rule.Behaviors[0].Options["name"]; name != "common" || len(rule.Behaviors[0].Options) != 1 {
+			t.Errorf("Value %v is invalid", rule.Behaviors[0].Options)
+		}
+	}
+
+	other := include(papi.OptionValue{"name": "other"})
+	unknown := include(papi.OptionValue{"id": "inc_2"})
+	canonicalIncludeBehaviors(other, unknown)
+	if other.Behaviors[0].Options["name"] != "other" || unknown.Behaviors[0].Options["id"] != "inc_2" {
+		t.Errorf("Value %v %v is invalid", other.Behaviors[0].Options, unknown.Behaviors[0].Options)
+	}
+}
+
+func TestPropertyIncludeCustomizeDiff(t *testing.T) {
+	rules := `{"rules": {"name": "default", "behaviors": [{"name": "caching", "options": {"behavior": "NO_STORE"}}]}}`
+	state := &terraform.InstanceState{
+		ID: "inc_1",
+		Attributes: map[string]string{
+			"contract":    "ctr_1",
+			"group":       "grp_2",
+			"product":     "prd_SPM",
+			"name":        "common",
+			"type":        "COMMON_SETTINGS",
+			"rule_format": "v2020-03-04",
+			"rules":       rules,
+			"version":     "3",
+		},
+	}
+	config := func(rules string) *terraform.ResourceConfig {
+		raw := map[string]interface{}{
+			"contract":    "ctr_1",
+			"group":       "grp_2",
+			"product":     "prd_SPM",
+			"name":        "common",
+			"type":        "COMMON_SETTINGS",
+			"rule_format": "v2020-03-04",
+			"rules":       rules,
+		}
+		return &terraform.ResourceConfig{Raw: raw, Config: raw}
+	}
+
+	diff, err := resourcePropertyInclude().Diff(state, config(`{"rules": {"name": "default", "behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE"}}]}}`), nil)
+	if err != nil || diff == nil || diff.Attributes["version"] == nil || !diff.Attributes["version"].NewComputed {
+		t.Errorf("Value %v is invalid: version should be computed: %v", diff, err)
+	}
+
+	diff, err = resourcePropertyInclude().Diff(state, config(rules), nil)
+	if err != nil || (diff != nil && diff.Attributes["version"] != nil) {
+		t.Errorf("Value %v is invalid: version should not change: %v", diff, err)
+	}
+}
+
+func TestPropertyIncludeActivationRead(t *testing.T) {
+	documents := map[string]map[string]interface{}{
+		"/papi/v1/includes/inc_1": {"includes": map[string]interface{}{"items": []interface{}{
+			map[string]interface{}{"includeId": "inc_1", "stagingVersion": 3},
+		}}},
+		"/papi/v1/includes/inc_1/activations": {"activations": map[string]interface{}{"items": []interface{}{
+			map[string]interface{}{"activationId": "atv_3", "activationType": "ACTIVATE", "includeVersion": 3, "network": "STAGING", "status": "ACTIVE"},
+			map[string]interface{}{"activationId": "atv_2", "activationType": "ACTIVATE", "includeVersion": 2, "network": "STAGING", "status": "ACTIVE"},
+		}}},
+	}
+	defer testCPRGServer(t, documents)()
+
+	d := resourcePropertyIncludeActivation().TestResourceData()
+	d.SetId("atv_2")
+	d.Set("include", "inc_1")
+	d.Set("contract", "ctr_1")
+	d.Set("group", "grp_1")
+	d.Set("network", "staging")
+	d.Set("version", 2)
+	if err := resourcePropertyIncludeActivationRead(d, nil); err != nil {
+		t.Fatalf("Value %v is invalid: %v", d.Id(), err)
+	}
+	if d.Id() != "atv_3" || d.Get("version").(int) != 3 {
+		t.Errorf("Value %v should be read back as the active version, got %v", d.Id(), d.Get("version"))
+	}
+
+	documents["/papi/v1/includes/inc_1"] = map[string]interface{}{"includes": map[string]interface{}{"items": []interface{}{
+		map[string]interface{}{"includeId": "inc_1"},
+	}}}
+	if err := resourcePropertyIncludeActivationRead(d, nil); err != nil {
+		t.Fatalf("Value %v is invalid: %v", d.Id(), err)
+	}
+	if d.Id() != "" {
+		t.Errorf("Value %v should be removed once deactivated", d.Id())
+	}
+}
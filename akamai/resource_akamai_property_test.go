@@ -269,3 +269,75 @@ func testAccCheckAkamaiPropertyExists(s *terraform.State) error {
 	}
 	return nil
 }
+
+func TestCreateOriginValues(t *testing.T) {
+	originConfig := map[string]interface{}{
+		"rule":                             "",
+		"origin_type":                      "CUSTOMER",
+		"hostname":                         "origin.example.org",
+		"port":                             80,
+		"https_port":                       8443,
+		"forward_hostname":                 "origin.example.org",
+		"cache_key_hostname":               "ORIGIN_HOSTNAME",
+		"compress":                         true,
+		"enable_true_client_ip":            false,
+		"net_storage":                      []interface{}{},
+		"origin_sni":                       "true",
+		"verification_mode":                "CUSTOM",
+		"custom_valid_cn_values":           []interface{}{"{{Origin Hostname}}"},
+		"origin_certs_to_honor":            "STANDARD_CERTIFICATE_AUTHORITIES",
+		"standard_certificate_authorities": []interface{}{"akamai-permissive"},
+		"custom_certificate_authorities":   []interface{}{},
+		"custom_certificates":              []interface{}{},
+	}
+
+	origin, err := createOriginValues(originConfig)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if origin["httpsPort"] != 8443 || origin["originSni"] != true || origin["forwardHostHeader"] != "CUSTOM" || origin["originCertsToHonor"] != "STANDARD_CERTIFICATE_AUTHORITIES" {
+		t.Errorf("unexpected origin options: %#v", origin)
+	}
+
+	unset := make(map[string]interface{})
+	for k, v := range originConfig {
+		unset[k] = v
+	}
+	unset["https_port"] = 0
+	unset["origin_sni"] = ""
+	unset["verification_mode"] = ""
+	origin, err = createOriginValues(unset)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, k := range []string{"httpsPort", "originSni", "verificationMode", "originCertsToHonor"} {
+		if _, ok := origin[k]; ok {
+			t.Errorf("unconfigured origin option %s should not be set: %#v", k, origin)
+		}
+	}
+
+	originConfig["origin_certs_to_honor"] = ""
+	if _, err := createOriginValues(originConfig); err == nil {
+		t.Errorf("CUSTOM verification without origin_certs_to_honor should be invalid")
+	}
+
+	originConfig["origin_type"] = "NET_STORAGE"
+	if _, err := createOriginValues(originConfig); err == nil {
+		t.Errorf("NET_STORAGE origin without net_storage should be invalid")
+	}
+
+	originConfig["net_storage"] = []interface{}{map[string]interface{}{
+		"download_domain_name": "example.download.akamai.com",
+		"cp_code":              12345,
+		"id":                   0,
+		"name":                 "",
+	}}
+	origin, err = createOriginValues(originConfig)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, ok := origin["hostname"]; ok {
+		t.Errorf("NET_STORAGE origin should not set hostname: %#v", origin)
+	}
+}
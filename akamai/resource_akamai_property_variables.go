@@ -1,11 +1,16 @@
 package akamai
 
 import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/jsonhooks-v1"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
 	"github.com/hashicorp/terraform/helper/schema"
-	"log"
-	"strings"
+	"github.com/tidwall/gjson"
 )
 
 func resourcePropertyVariables() *schema.Resource {
@@ -34,8 +39,9 @@ var akamaiPropertyVariablesSchema = map[string]*schema.Schema{
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
 							"name": {
-								Type:     schema.TypeString,
-								Required: true,
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validatePropertyVariableName,
 							},
 							"hidden": {
 								Type:     schema.TypeBool,
@@ -54,6 +60,11 @@ var akamaiPropertyVariablesSchema = map[string]*schema.Schema{
 								Type:     schema.TypeString,
 								Optional: true,
 							},
+							"sensitive_value": {
+								Type:      schema.TypeString,
+								Optional:  true,
+								Sensitive: true,
+							},
 						},
 					},
 				},
@@ -65,10 +76,79 @@ var akamaiPropertyVariablesSchema = map[string]*schema.Schema{
 		Computed:    true,
 		Description: "JSON variables representation",
 	},
+	"sensitive_values": {
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Sensitive:   true,
+		Description: "Values of the sensitive variables, which json leaves out",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	},
+}
+
+var (
+	propertyVariableNameRegexp      = regexp.MustCompile(`^PMUSER_[A-Z0-9_]+$`)
+	propertyVariableReferenceRegexp = regexp.MustCompile(`\{\{user\.(PMUSER_[A-Za-z0-9_]+)\}\}`)
+)
+
+// validatePropertyVariableName is a SchemaValidateFunc to validate PAPI user variable names.
+func validatePropertyVariableName(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	if !propertyVariableNameRegexp.MatchString(value) {
+		es = append(es, fmt.Errorf("%s %q must start with PMUSER_ and contain only uppercase letters, digits and underscores", k, value))
+	}
+	return
+}
+
+// undeclaredPropertyVariables returns the PMUSER_ variables referenced as
+// {{user.PMUSER_X}} in the rules JSON which are neither declared in the rules
+// nor in the variables JSON.
+func undeclaredPropertyVariables(rulesJSON string, variablesJSON string) []string {
+	declared := make(map[string]bool)
+	for _, name := range gjson.Get(rulesJSON, "rules.variables.#.name").Array() {
+		declared[name.String()] = true
+	}
+	for _, name := range gjson.Get(variablesJSON, "variables.#.name").Array() {
+		declared[name.String()] = true
+	}
+
+	undeclared := make(map[string]bool)
+	for _, match := range propertyVariableReferenceRegexp.FindAllStringSubmatch(rulesJSON, -1) {
+		if !declared[match[1]] {
+			undeclared[match[1]] = true
+		}
+	}
+
+	names := make([]string, 0, len(undeclared))
+	for name := range undeclared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// propertyVariableValue returns the value of a configured variable. Sensitive
+// variables take it from sensitive_value, which is redacted in plans, and
+// other variables from value, which is not.
+func propertyVariableValue(variableMap map[string]interface{}) (string, error) {
+	name := variableMap["name"].(string)
+	value, _ := variableMap["value"].(string)
+	sensitiveValue, _ := variableMap["sensitive_value"].(string)
+
+	if variableMap["sensitive"].(bool) {
+		if value != "" {
+			return "", fmt.Errorf("variable %s is sensitive, set its value with sensitive_value", name)
+		}
+		return sensitiveValue, nil
+	}
+	if sensitiveValue != "" {
+		return "", fmt.Errorf("variable %s is not sensitive, set its value with value", name)
+	}
+	return value, nil
 }
 
 func resourcePropertyVariablesCreate(d *schema.ResourceData, meta interface{}) error {
 	rule := papi.NewRule()
+	sensitiveValues := make(map[string]interface{})
 	log.Printf("[DEBUG] START Check for variables")
 	variables, ok := d.GetOk("variables")
 	if ok {
@@ -92,9 +172,17 @@ func resourcePropertyVariablesCreate(d *schema.ResourceData, meta interface{}) e
 						newVariable := papi.NewVariable()
 						newVariable.Name = variableMap["name"].(string)
 						newVariable.Description = variableMap["description"].(string)
-						newVariable.Value = variableMap["value"].(string)
 						newVariable.Hidden = variableMap["hidden"].(bool)
 						newVariable.Sensitive = variableMap["sensitive"].(bool)
+						value, err := propertyVariableValue(variableMap)
+						if err != nil {
+							return err
+						}
+						if newVariable.Sensitive {
+							sensitiveValues[newVariable.Name] = value
+						} else {
+							newVariable.Value = value
+						}
 						rule.AddVariable(newVariable)
 					}
 				}
@@ -112,6 +200,7 @@ func resourcePropertyVariablesCreate(d *schema.ResourceData, meta interface{}) e
 
 	sha := getSHAString(string(jsonBody))
 	d.Set("json", string(jsonBody))
+	d.Set("sensitive_values", sensitiveValues)
 
 	d.SetId(sha)
 	log.Println("[DEBUG] Done")
@@ -186,6 +275,7 @@ func resourcePropertyVariablesRead(d *schema.ResourceData, meta interface{}) err
 func resourcePropertyVariablesUpdate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] UPDATING")
 	rule := papi.NewRule()
+	sensitiveValues := make(map[string]interface{})
 	log.Printf("[DEBUG] START Check for variables")
 	variables, ok := d.GetOk("variables")
 	if ok {
@@ -203,9 +293,17 @@ func resourcePropertyVariablesUpdate(d *schema.ResourceData, meta interface{}) e
 							newVariable := papi.NewVariable()
 							newVariable.Name = variableMap["name"].(string)
 							newVariable.Description = variableMap["description"].(string)
-							newVariable.Value = variableMap["value"].(string)
 							newVariable.Hidden = variableMap["hidden"].(bool)
 							newVariable.Sensitive = variableMap["sensitive"].(bool)
+							value, err := propertyVariableValue(variableMap)
+							if err != nil {
+								return err
+							}
+							if newVariable.Sensitive {
+								sensitiveValues[newVariable.Name] = value
+							} else {
+								newVariable.Value = value
+							}
 							rule.AddVariable(newVariable)
 						}
 					}
@@ -223,6 +321,7 @@ func resourcePropertyVariablesUpdate(d *schema.ResourceData, meta interface{}) e
 
 		sha := getSHAString(string(jsonBody))
 		d.Set("json", string(jsonBody))
+		d.Set("sensitive_values", sensitiveValues)
 
 		d.SetId(sha)
 	}
@@ -57,3 +57,58 @@ func testAccCheckAkamaiPropertyVariablesDestroy(s *terraform.State) error {
 	}
 	return nil
 }
+
+func TestValidatePropertyVariableName(t *testing.T) {
+	badValues := []string{"ORIGIN", "PMUSER_", "pmuser_origin", "PMUSER_ORIGIN-HOST"}
+	goodValues := []string{"PMUSER_ORIGIN", "PMUSER_ORIGIN_2"}
+
+	for _, bv := range badValues {
+		_, err := validatePropertyVariableName(bv, "name")
+		if err == nil {
+			t.Errorf("Value %v should be invalid", bv)
+		}
+	}
+
+	for _, gv := range goodValues {
+		_, err := validatePropertyVariableName(gv, "name")
+		if err != nil {
+			t.Errorf("Value %v is invalid: %v", gv, err)
+		}
+	}
+}
+
+func TestUndeclaredPropertyVariables(t *testing.T) {
+	rules := `{"rules":{"name":"default","variables":[{"name":"PMUSER_A"}],"behaviors":[{"name":"origin","options":{"hostname":"{{user.PMUSER_A}}.{{user.PMUSER_B}}.{{user.PMUSER_C}}"}}]}}`
+	variables := `{"name":"","variables":[{"name":"PMUSER_B"}]}`
+
+	undeclared := undeclaredPropertyVariables(rules, variables)
+	if len(undeclared) != 1 || undeclared[0] != "PMUSER_C" {
+		t.Errorf("unexpected undeclared variables: %v", undeclared)
+	}
+}
+
+func TestPropertyVariableValue(t *testing.T) {
+	tests := []struct {
+		variable map[string]interface{}
+		value    string
+		valid    bool
+	}{
+		{map[string]interface{}{"name": "PMUSER_ORIGIN", "sensitive": false, "value": "origin.example.org", "sensitive_value": ""}, "origin.example.org", true},
+		{map[string]interface{}{"name": "PMUSER_SECRET", "sensitive": true, "value": "", "sensitive_value": "s3cr3t"}, "s3cr3t", true},
+		{map[string]interface{}{"name": "PMUSER_SECRET", "sensitive": true, "value": "s3cr3t", "sensitive_value": ""}, "", false},
+		{map[string]interface{}{"name": "PMUSER_ORIGIN", "sensitive": false, "value": "", "sensitive_value": "origin.example.org"}, "", false},
+	}
+
+	for _, tt := range tests {
+		value, err := propertyVariableValue(tt.variable)
+		if tt.valid && err != nil {
+			t.Errorf("Value %v is invalid: %v", tt.variable, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("Value %v should be invalid", tt.variable)
+		}
+		if value != tt.value {
+			t.Errorf("propertyVariableValue(%v) = %v", tt.variable, value)
+		}
+	}
+}
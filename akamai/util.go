@@ -4,10 +4,17 @@ import (
 	"crypto/sha1"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"os"
 	"reflect"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/client-v1"
+	dnsv2 "github.com/akamai/AkamaiOPEN-edgegrid-golang/configdns-v2"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/papi-v1"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/patrickmn/go-cache"
 )
@@ -97,3 +104,77 @@ func makeCache() *cache.Cache {
 	profilecache := cache.New(5*time.Minute, 10*time.Minute)
 	return profilecache
 }
+
+// papiDo sends a JSON request signed with the property credentials and decodes
+// the response into result, for endpoints papi-v1 does not wrap.
+func papiDo(method, path string, body interface{}, result interface{}) error {
+	return jsonDo(papi.Config, method, path, body, result)
+}
+
+// dnsDo sends a JSON request signed with the DNS credentials and decodes the
+// response into result, for endpoints configdns-v2 does not wrap.
+func dnsDo(method, path string, body interface{}, result interface{}) error {
+	return jsonDo(dnsv2.Config, method, path, body, result)
+}
+
+func jsonDo(config edgegrid.Config, method, path string, body interface{}, result interface{}) error {
+	req, err := client.NewJSONRequest(config, method, path, body)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(config, req)
+	if err != nil {
+		return err
+	}
+
+	if client.IsError(res) {
+		return client.NewAPIError(res)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return client.BodyJSON(res, result)
+}
+
+// splitImportID splits a colon separated import ID into exactly n non-empty parts.
+func splitImportID(id string, n int, format string) ([]string, error) {
+	parts := strings.Split(id, ":")
+	if len(parts) != n {
+		return nil, fmt.Errorf("import ID %q must be in the form %s", id, format)
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("import ID %q must be in the form %s", id, format)
+		}
+	}
+
+	return parts, nil
+}
+
+// namedLocks hands out one mutex per name, so writes to the same object are
+// serialized without blocking writes to other objects.
+type namedLocks struct {
+	sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func newNamedLocks() *namedLocks {
+	return &namedLocks{locks: make(map[string]*sync.Mutex)}
+}
+
+// lock locks name and returns the function that unlocks it.
+func (n *namedLocks) lock(name string) func() {
+	n.Lock()
+	lock, ok := n.locks[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		n.locks[name] = lock
+	}
+	n.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}